@@ -5,3 +5,5 @@ go 1.24.1
 require github.com/nsf/termbox-go v1.1.1
 
 require github.com/mattn/go-runewidth v0.0.9
+
+require golang.org/x/text v0.34.0
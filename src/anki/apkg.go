@@ -0,0 +1,418 @@
+package anki
+
+import (
+	"archive/zip"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"html"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Your-RoGr/DeckBuilder/src/dataFrame"
+	"github.com/Your-RoGr/DeckBuilder/src/deckConfig"
+)
+
+// now returns the current time, replaced in tests
+var now = time.Now
+
+// Options of an exported package
+type Options struct {
+	Deck     string // Anki deck of notes without a value in the Deck column
+	Reversed bool   // add a card from the translation to the word for every note
+}
+
+// The schema of an Anki collection, version 11, as in collection.anki2 of .apkg files
+const (
+	schemaCol     = "CREATE TABLE col (id integer primary key, crt integer not null, mod integer not null, scm integer not null, ver integer not null, dty integer not null, usn integer not null, ls integer not null, conf text not null, models text not null, decks text not null, dconf text not null, tags text not null)"
+	schemaNotes   = "CREATE TABLE notes (id integer primary key, guid text not null, mid integer not null, mod integer not null, usn integer not null, tags text not null, flds text not null, sfld integer not null, csum integer not null, flags integer not null, data text not null)"
+	schemaCards   = "CREATE TABLE cards (id integer primary key, nid integer not null, did integer not null, ord integer not null, mod integer not null, usn integer not null, type integer not null, queue integer not null, due integer not null, ivl integer not null, factor integer not null, reps integer not null, lapses integer not null, left integer not null, odue integer not null, odid integer not null, flags integer not null, data text not null)"
+	schemaRevlog  = "CREATE TABLE revlog (id integer primary key, cid integer not null, usn integer not null, ease integer not null, ivl integer not null, lastIvl integer not null, factor integer not null, time integer not null, type integer not null)"
+	schemaGraves  = "CREATE TABLE graves (usn integer not null, oid integer not null, type integer not null)"
+	schemaVersion = 11
+)
+
+// Positions of the indexed columns in the rows of the notes and cards tables
+const (
+	notesUsn   = 4
+	notesCsum  = 8
+	cardsNid   = 1
+	cardsDid   = 2
+	cardsUsn   = 5
+	cardsQueue = 7
+	cardsDue   = 8
+)
+
+// WritePackage writes df as an Anki package (.apkg). Every row becomes a note of a Basic
+// notetype whose fields are the columns of the deck; the Tags and Deck columns set the
+// tags and deck of the note. The GUID of a note is derived from its first field, so
+// exporting the deck again updates the notes already imported instead of duplicating them
+func WritePackage(w io.Writer, df *dataFrame.DataFrame, options Options) error {
+
+	db, err := buildCollection(df, options)
+	if err != nil {
+		return err
+	}
+
+	archive := zip.NewWriter(w)
+
+	file, err := archive.Create("collection.anki2")
+	if err != nil {
+		return err
+	}
+
+	if _, err := db.WriteTo(file); err != nil {
+		return err
+	}
+
+	// No media files are included
+	media, err := archive.Create("media")
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(media, "{}"); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+// noteType describes the notetype of the exported notes
+type noteType struct {
+	id       int64
+	name     string
+	fields   []string
+	reversed bool
+}
+
+// buildCollection builds the collection database of the package
+func buildCollection(df *dataFrame.DataFrame, options Options) (*database, error) {
+
+	tagsIdx := slices.Index(df.Columns, deckConfig.ColumnTags)
+	deckIdx := slices.Index(df.Columns, deckConfig.ColumnDeck)
+
+	var fieldIdx []int
+	model := noteType{name: "DeckBuilder Basic", reversed: options.Reversed}
+
+	for i, column := range df.Columns {
+		if i != tagsIdx && i != deckIdx {
+			fieldIdx = append(fieldIdx, i)
+			model.fields = append(model.fields, column)
+		}
+	}
+
+	if len(model.fields) == 0 {
+		return nil, errors.New("the deck has no columns for note fields")
+	}
+
+	// A reversed card needs a second field to show on its front
+	model.reversed = model.reversed && len(model.fields) > 1
+	if model.reversed {
+		model.name += " (and reversed card)"
+	}
+
+	model.id = stableID("notetype", model.name, strings.Join(model.fields, "\x1f"))
+
+	defaultDeck := strings.TrimSpace(options.Deck)
+	if defaultDeck == "" {
+		defaultDeck = "Default"
+	}
+
+	t := now()
+	base := t.UnixMilli()
+	decks := map[string]int64{}
+
+	var notes, cards []row
+
+	for _, record := range df.Data {
+
+		values := make([]string, len(fieldIdx))
+		for i, idx := range fieldIdx {
+			values[i] = cellValue(record, idx)
+		}
+
+		if strings.TrimSpace(values[0]) == "" {
+			continue
+		}
+
+		deckName := strings.TrimSpace(cellValue(record, deckIdx))
+		if deckName == "" {
+			deckName = defaultDeck
+		}
+
+		did, ok := decks[deckName]
+		if !ok {
+			did = deckID(deckName)
+			decks[deckName] = did
+		}
+
+		nid := base + int64(len(notes))
+		notes = append(notes, noteRow(nid, model.id, t, values, cellValue(record, tagsIdx)))
+
+		for ord := 0; ord < model.templates(); ord++ {
+			cards = append(cards, cardRow(base+int64(len(cards)), nid, did, ord, t, len(notes)))
+		}
+	}
+
+	col, err := colRow(model, decks, len(notes), t)
+	if err != nil {
+		return nil, err
+	}
+
+	db := newDatabase()
+	db.createTable("col", schemaCol, []row{col})
+	db.createTable("notes", schemaNotes, notes)
+	db.createTable("cards", schemaCards, cards)
+	db.createTable("revlog", schemaRevlog, nil)
+	db.createTable("graves", schemaGraves, nil)
+	db.createIndex("ix_notes_usn", "notes", "CREATE INDEX ix_notes_usn on notes (usn)", notes, notesUsn)
+	db.createIndex("ix_cards_usn", "cards", "CREATE INDEX ix_cards_usn on cards (usn)", cards, cardsUsn)
+	db.createIndex("ix_revlog_usn", "revlog", "CREATE INDEX ix_revlog_usn on revlog (usn)", nil)
+	db.createIndex("ix_cards_nid", "cards", "CREATE INDEX ix_cards_nid on cards (nid)", cards, cardsNid)
+	db.createIndex("ix_cards_sched", "cards", "CREATE INDEX ix_cards_sched on cards (did, queue, due)", cards, cardsDid, cardsQueue, cardsDue)
+	db.createIndex("ix_revlog_cid", "revlog", "CREATE INDEX ix_revlog_cid on revlog (cid)", nil)
+	db.createIndex("ix_notes_csum", "notes", "CREATE INDEX ix_notes_csum on notes (csum)", notes, notesCsum)
+
+	return db, nil
+}
+
+// templates returns the number of card templates, and so of cards per note
+func (m noteType) templates() int {
+
+	if m.reversed {
+		return 2
+	}
+
+	return 1
+}
+
+// noteRow returns the row of a note with the given field values and tags
+func noteRow(id, mid int64, t time.Time, values []string, tags string) row {
+
+	fields := make([]string, len(values))
+	for i, v := range values {
+		fields[i] = fieldHTML(v)
+	}
+
+	tags = strings.Join(strings.Fields(tags), " ")
+	if tags != "" {
+		tags = " " + tags + " "
+	}
+
+	return row{id: id, values: []any{
+		nil,                          // id
+		guid(values[0]),              // guid
+		mid,                          // mid
+		t.Unix(),                     // mod
+		int64(-1),                    // usn
+		tags,                         // tags
+		strings.Join(fields, "\x1f"), // flds
+		values[0],                    // sfld
+		checksum(values[0]),          // csum
+		int64(0),                     // flags
+		"",                           // data
+	}}
+}
+
+// cardRow returns the row of a new card of a note. New cards are shown in the order of due
+func cardRow(id, nid, did int64, ord int, t time.Time, due int) row {
+
+	values := []any{nil, nid, did, int64(ord), t.Unix(), int64(-1)}
+
+	// type, queue, due, ivl, factor, reps, lapses, left, odue, odid, flags
+	values = append(values, int64(0), int64(0), int64(due), int64(0), int64(0), int64(0), int64(0), int64(0), int64(0), int64(0), int64(0))
+
+	return row{id: id, values: append(values, "")}
+}
+
+// colRow returns the row of the col table holding the notetype, the decks and the settings
+func colRow(model noteType, decks map[string]int64, notes int, t time.Time) (row, error) {
+
+	conf := map[string]any{
+		"activeDecks":   []int64{1},
+		"addToCur":      true,
+		"collapseTime":  1200,
+		"curDeck":       1,
+		"curModel":      strconv.FormatInt(model.id, 10),
+		"dueCounts":     true,
+		"estTimes":      true,
+		"newBury":       true,
+		"newSpread":     0,
+		"nextPos":       notes + 1,
+		"sortBackwards": false,
+		"sortType":      "noteFld",
+		"timeLim":       0,
+	}
+
+	deckList := map[string]any{"1": deckJSON(1, "Default", t)}
+	for name, id := range decks {
+		deckList[strconv.FormatInt(id, 10)] = deckJSON(id, name, t)
+	}
+
+	var values []any
+	values = append(values, nil, t.Truncate(24*time.Hour).Unix(), t.UnixMilli(), t.UnixMilli(), int64(schemaVersion))
+	values = append(values, int64(0), int64(0), int64(0)) // dty, usn, ls
+
+	for _, v := range []any{conf, map[string]any{strconv.FormatInt(model.id, 10): model.json(t)}, deckList, deckOptionsJSON, map[string]any{}} {
+
+		data, err := json.Marshal(v)
+		if err != nil {
+			return row{}, err
+		}
+
+		values = append(values, string(data))
+	}
+
+	return row{id: 1, values: values}, nil
+}
+
+// json returns the description of the notetype stored in col.models
+func (m noteType) json(t time.Time) map[string]any {
+
+	fields := make([]map[string]any, len(m.fields))
+	for i, name := range m.fields {
+		fields[i] = map[string]any{
+			"name": name, "ord": i, "sticky": false, "rtl": false,
+			"font": "Arial", "size": 20, "media": []any{},
+		}
+	}
+
+	answer := "{{FrontSide}}\n\n<hr id=answer>\n\n"
+	back := "{{FrontSide}}"
+	if len(m.fields) > 1 {
+		back = answer + strings.Join(templateFields(m.fields[1:]), "<br>\n")
+	}
+
+	templates := []map[string]any{template("Card 1", 0, "{{"+m.fields[0]+"}}", back)}
+	if m.reversed {
+		templates = append(templates, template("Card 2", 1, "{{"+m.fields[1]+"}}", answer+"{{"+m.fields[0]+"}}"))
+	}
+
+	req := []any{[]any{0, "any", []int{0}}}
+	if m.reversed {
+		req = append(req, []any{1, "any", []int{1}})
+	}
+
+	return map[string]any{
+		"id":        m.id,
+		"name":      m.name,
+		"type":      0,
+		"mod":       t.Unix(),
+		"usn":       -1,
+		"sortf":     0,
+		"did":       1,
+		"tmpls":     templates,
+		"flds":      fields,
+		"css":       ".card {\n font-family: arial;\n font-size: 20px;\n text-align: center;\n color: black;\n background-color: white;\n}\n",
+		"latexPre":  "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage[utf8]{inputenc}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n",
+		"latexPost": "\\end{document}",
+		"latexsvg":  false,
+		"req":       req,
+		"tags":      []any{},
+		"vers":      []any{},
+	}
+}
+
+// templateFields returns the field references of names for a template
+func templateFields(names []string) []string {
+
+	refs := make([]string, len(names))
+	for i, name := range names {
+		refs[i] = "{{" + name + "}}"
+	}
+
+	return refs
+}
+
+// template returns the description of a card template
+func template(name string, ord int, front, back string) map[string]any {
+	return map[string]any{
+		"name": name, "ord": ord, "qfmt": front, "afmt": back,
+		"bqfmt": "", "bafmt": "", "did": nil, "bfont": "", "bsize": 0,
+	}
+}
+
+// deckJSON returns the description of a deck stored in col.decks
+func deckJSON(id int64, name string, t time.Time) map[string]any {
+	return map[string]any{
+		"id": id, "name": name, "mod": t.Unix(), "usn": -1, "desc": "", "dyn": 0, "conf": 1,
+		"collapsed": false, "browserCollapsed": false, "extendNew": 0, "extendRev": 0,
+		"newToday": []int{0, 0}, "revToday": []int{0, 0}, "lrnToday": []int{0, 0}, "timeToday": []int{0, 0},
+	}
+}
+
+// deckOptionsJSON are the default deck options stored in col.dconf
+var deckOptionsJSON = map[string]any{
+	"1": map[string]any{
+		"id": 1, "name": "Default", "mod": 0, "usn": 0, "maxTaken": 60, "autoplay": true, "timer": 0, "replayq": true, "dyn": false,
+		"new":   map[string]any{"bury": false, "delays": []float64{1, 10}, "initialFactor": 2500, "ints": []int{1, 4, 0}, "order": 1, "perDay": 20},
+		"rev":   map[string]any{"bury": false, "ease4": 1.3, "ivlFct": 1, "maxIvl": 36500, "perDay": 200, "hardFactor": 1.2},
+		"lapse": map[string]any{"delays": []float64{10}, "leechAction": 1, "leechFails": 8, "minInt": 1, "mult": 0},
+	},
+}
+
+// fieldHTML converts the value of a deck cell to the HTML of an Anki field
+func fieldHTML(value string) string {
+	return strings.ReplaceAll(html.EscapeString(value), "\n", "<br>")
+}
+
+// checksum returns the checksum Anki keeps of the first field to find duplicates:
+// the first 8 hex digits of its SHA-1
+func checksum(field string) int64 {
+
+	sum := sha1.Sum([]byte(field))
+	n, _ := strconv.ParseInt(hex.EncodeToString(sum[:4]), 16, 64)
+
+	return n
+}
+
+// guidChars are the characters of the base91 encoding Anki uses for note GUIDs
+const guidChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!#$%&()*+,-./:;<=>?@[]^_`{|}~"
+
+// guid returns the GUID of the note whose first field is word
+func guid(word string) string {
+
+	sum := sha1.Sum([]byte("deckbuilder\x00" + word))
+	n := binary.BigEndian.Uint64(sum[:8])
+
+	var b []byte
+	for n > 0 {
+		b = append(b, guidChars[n%uint64(len(guidChars))])
+		n /= uint64(len(guidChars))
+	}
+
+	slices.Reverse(b)
+
+	return string(b)
+}
+
+// deckID returns the ID of the deck with the given name. The same name always gets
+// the same ID, which is never that of Anki's Default deck
+func deckID(name string) int64 {
+	return stableID("deck", name)
+}
+
+// stableID returns an ID derived from parts, in the range Anki uses for IDs made from
+// millisecond timestamps, so that exporting again refers to the same notetype or deck
+func stableID(parts ...string) int64 {
+
+	sum := sha1.Sum([]byte(strings.Join(parts, "\x00")))
+
+	return 1<<40 + int64(binary.BigEndian.Uint64(sum[:8])%(1<<40))
+}
+
+// cellValue returns the value at column idx of row, or "" if there is none
+func cellValue(record []string, idx int) string {
+
+	if idx < 0 || idx >= len(record) {
+		return ""
+	}
+
+	return record[idx]
+}
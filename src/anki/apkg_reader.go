@@ -0,0 +1,252 @@
+package anki
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Your-RoGr/DeckBuilder/src/dataFrame"
+	"github.com/Your-RoGr/DeckBuilder/src/deckConfig"
+)
+
+// collectionFiles are the names of the collection in a package, the preferred first.
+// Packages exported for older Anki versions have collection.anki2 only
+var collectionFiles = []string{"collection.anki21", "collection.anki2"}
+
+// note is a note read from a package
+type note struct {
+	fields []string
+	tags   string
+	deck   string
+}
+
+// modelField is a field of a notetype as kept in col.models
+type modelField struct {
+	Name string `json:"name"`
+	Ord  int    `json:"ord"`
+}
+
+// maxCollectionSize is the size of the largest collection read from a package
+const maxCollectionSize = 1 << 30
+
+// ReadPackage reads the notes of an Anki package (.apkg or .colpkg). The fields of the
+// notetypes become the columns, in the order of the notetypes and their fields, followed
+// by the Tags and Deck columns. Fields are returned as stored by Anki, with HTML
+func ReadPackage(r io.ReaderAt, size int64) (*dataFrame.DataFrame, error) {
+
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := readCollection(archive)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := openDatabaseFile(data)
+	if err != nil {
+		return nil, err
+	}
+
+	models, decks, err := readCol(db)
+	if err != nil {
+		return nil, err
+	}
+
+	noteDecks, err := readCardDecks(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var columns []string
+	var notes []note
+
+	err = db.scanTable("notes", func(id int64, values []any) error {
+
+		if len(values) < 7 {
+			return errMalformed
+		}
+
+		mid, _ := values[2].(int64)
+		tags, _ := values[5].(string)
+		flds, _ := values[6].(string)
+
+		model, ok := models[mid]
+		if !ok {
+			return errors.New("a note refers to a missing notetype")
+		}
+
+		for _, name := range model {
+			if !slices.Contains(columns, name) {
+				columns = append(columns, name)
+			}
+		}
+
+		fields := strings.Split(flds, "\x1f")
+		n := note{
+			fields: make([]string, len(columns)),
+			tags:   strings.Join(strings.Fields(tags), " "),
+			deck:   decks[noteDecks[id]],
+		}
+
+		for i, name := range model {
+			if i < len(fields) {
+				n.fields[slices.Index(columns, name)] = fields[i]
+			}
+		}
+
+		notes = append(notes, n)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	df := dataFrame.NewDataFrame(';')
+	df.Columns = append(columns, deckConfig.ColumnTags, deckConfig.ColumnDeck)
+	df.Data = make([][]string, len(notes))
+
+	// Notes read before a later notetype added columns have fewer fields
+	for i, n := range notes {
+
+		row := make([]string, len(df.Columns))
+		copy(row, n.fields)
+
+		row[len(columns)], row[len(columns)+1] = n.tags, n.deck
+		df.Data[i] = row
+	}
+
+	return df, nil
+}
+
+// readCollection returns the contents of the collection database of a package
+func readCollection(archive *zip.Reader) ([]byte, error) {
+
+	files := make(map[string]*zip.File)
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	// Since Anki 2.1.50 packages hold a compressed collection.anki21b, and their
+	// collection.anki2 only has a note asking to update Anki
+	if _, ok := files["collection.anki21b"]; ok && files["collection.anki21"] == nil {
+		return nil, errors.New("the package uses the format of Anki 2.1.50+; export it again with \"Support older Anki versions\" checked")
+	}
+
+	for _, name := range collectionFiles {
+
+		file, ok := files[name]
+		if !ok {
+			continue
+		}
+
+		if file.UncompressedSize64 > maxCollectionSize {
+			return nil, errors.New("the collection of the package is too large")
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+
+		return io.ReadAll(io.LimitReader(rc, maxCollectionSize))
+	}
+
+	return nil, errors.New("not an Anki package: no collection.anki2 inside")
+}
+
+// readCol returns the field names of the notetypes and the names of the decks
+// kept in the col table
+func readCol(db *databaseFile) (map[int64][]string, map[int64]string, error) {
+
+	var modelsJSON, decksJSON string
+
+	err := db.scanTable("col", func(id int64, values []any) error {
+
+		if len(values) < 11 {
+			return errMalformed
+		}
+
+		modelsJSON, _ = values[9].(string)
+		decksJSON, _ = values[10].(string)
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var models map[string]struct {
+		Flds []modelField `json:"flds"`
+	}
+
+	if err := json.Unmarshal([]byte(modelsJSON), &models); err != nil || len(models) == 0 {
+		return nil, nil, errors.New("the collection has no notetypes that can be read")
+	}
+
+	fields := make(map[int64][]string, len(models))
+
+	for id, model := range models {
+
+		mid, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return nil, nil, errMalformed
+		}
+
+		slices.SortFunc(model.Flds, func(a, b modelField) int { return a.Ord - b.Ord })
+
+		for _, f := range model.Flds {
+			fields[mid] = append(fields[mid], f.Name)
+		}
+	}
+
+	var decks map[string]struct {
+		Name string `json:"name"`
+	}
+
+	// Without deck names the notes are read without their decks
+	_ = json.Unmarshal([]byte(decksJSON), &decks)
+
+	names := make(map[int64]string, len(decks))
+	for id, deck := range decks {
+		if did, err := strconv.ParseInt(id, 10, 64); err == nil {
+			names[did] = deck.Name
+		}
+	}
+
+	return fields, names, nil
+}
+
+// readCardDecks returns the deck of every note: the deck of its first card
+func readCardDecks(db *databaseFile) (map[int64]int64, error) {
+
+	decks := make(map[int64]int64)
+	ords := make(map[int64]int64)
+
+	err := db.scanTable("cards", func(id int64, values []any) error {
+
+		if len(values) < 4 {
+			return errMalformed
+		}
+
+		nid, _ := values[1].(int64)
+		did, _ := values[2].(int64)
+		ord, _ := values[3].(int64)
+
+		if current, ok := ords[nid]; !ok || ord < current {
+			decks[nid] = did
+			ords[nid] = ord
+		}
+
+		return nil
+	})
+
+	return decks, err
+}
@@ -0,0 +1,208 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Your-RoGr/DeckBuilder/src/dataFrame"
+)
+
+// unpack writes df as a package and returns the path of its extracted collection
+// and the contents of its media manifest
+func unpack(t *testing.T, df *dataFrame.DataFrame, options Options) (string, string) {
+
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := WritePackage(&buf, df, options); err != nil {
+		t.Fatalf("WritePackage: %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip.NewReader: %v", err)
+	}
+
+	files := map[string][]byte{}
+
+	for _, file := range archive.File {
+
+		r, err := file.Open()
+		if err != nil {
+			t.Fatalf("Open %s: %v", file.Name, err)
+		}
+
+		data, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatalf("ReadAll %s: %v", file.Name, err)
+		}
+
+		files[file.Name] = data
+	}
+
+	collection := filepath.Join(t.TempDir(), "collection.anki2")
+	if err := os.WriteFile(collection, files["collection.anki2"], 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return collection, string(files["media"])
+}
+
+func TestWritePackage(t *testing.T) {
+
+	now = func() time.Time { return time.UnixMilli(1700000000000) }
+	defer func() { now = time.Now }()
+
+	df := &dataFrame.DataFrame{
+		Columns: []string{"Word", "Translation", "Tags", "Deck"},
+		Data: [][]string{
+			{"cat", "кот", "animals noun", ""},
+			{"dog", "<собака>\nпёс", "", "Pets"},
+			{"", "skipped", "", ""},
+		},
+	}
+
+	collection, media := unpack(t, df, Options{Deck: "English", Reversed: true})
+
+	if media != "{}" {
+		t.Errorf("media = %q, want {}", media)
+	}
+
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"PRAGMA integrity_check", "ok"},
+		{"SELECT ver FROM col", "11"},
+		{"SELECT count(*) FROM notes", "2"},
+		{"SELECT count(*) FROM cards", "4"},
+		{"SELECT sfld, '[' || tags || ']' FROM notes ORDER BY id LIMIT 1", "cat|[ animals noun ]"},
+		{"SELECT replace(flds, char(31), '|') FROM notes WHERE sfld = 'dog'", "dog|&lt;собака&gt;<br>пёс"},
+		{"SELECT count(*) FROM cards WHERE nid = (SELECT id FROM notes WHERE sfld = 'dog')", "2"},
+		{"SELECT count(DISTINCT did) FROM cards", "2"},
+		{"SELECT csum FROM notes WHERE sfld = 'cat'", "2644024973"}, // 0x9d989e8d from sha1("cat")
+	}
+
+	for _, tt := range tests {
+		if got := sqlite3(t, collection, tt.query); got != tt.expected {
+			t.Errorf("%s = %q, want %q", tt.query, got, tt.expected)
+		}
+	}
+}
+
+func TestWritePackage_stableIDs(t *testing.T) {
+
+	df := &dataFrame.DataFrame{
+		Columns: []string{"Word", "Translation"},
+		Data:    [][]string{{"cat", "кот"}},
+	}
+
+	first, _ := unpack(t, df, Options{Deck: "English"})
+	second, _ := unpack(t, df, Options{Deck: "English"})
+
+	query := "SELECT n.guid, n.mid, c.did FROM notes n JOIN cards c ON c.nid = n.id"
+
+	if a, b := sqlite3(t, first, query), sqlite3(t, second, query); a != b {
+		t.Errorf("exporting again changed the note: %q and %q", a, b)
+	}
+}
+
+func TestWritePackage_noColumns(t *testing.T) {
+
+	df := &dataFrame.DataFrame{Columns: []string{"Tags"}}
+
+	if err := WritePackage(io.Discard, df, Options{}); err == nil {
+		t.Error("expected an error for a deck without field columns")
+	}
+}
+
+func TestGuid(t *testing.T) {
+
+	if guid("cat") != guid("cat") {
+		t.Error("guid is not stable")
+	}
+
+	if guid("cat") == guid("dog") {
+		t.Error("guid is the same for different words")
+	}
+
+	for _, r := range guid("cat") {
+		if !bytes.ContainsRune([]byte(guidChars), r) {
+			t.Errorf("guid contains %q", r)
+		}
+	}
+}
+
+func TestReadPackage(t *testing.T) {
+
+	df := &dataFrame.DataFrame{
+		Columns: []string{"Word", "Translation", "Tags", "Deck"},
+		Data: [][]string{
+			{"cat", "кот", "animals  noun", ""},
+			{"dog", "<собака>\nпёс", "", "Pets"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WritePackage(&buf, df, Options{Deck: "English", Reversed: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := ReadPackage(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("ReadPackage: %v", err)
+	}
+
+	expected := [][]string{
+		{"cat", "кот", "animals noun", "English"},
+		{"dog", "&lt;собака&gt;<br>пёс", "", "Pets"},
+	}
+
+	if !reflect.DeepEqual(read.Columns, df.Columns) {
+		t.Errorf("Columns = %v, want %v", read.Columns, df.Columns)
+	}
+
+	if !reflect.DeepEqual(read.Data, expected) {
+		t.Errorf("Data = %q, want %q", read.Data, expected)
+	}
+}
+
+func TestReadPackage_invalid(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{"no collection", map[string]string{"media": "{}"}},
+		{"new format", map[string]string{"collection.anki21b": "", "collection.anki2": "", "media": ""}},
+		{"not a database", map[string]string{"collection.anki2": "text"}},
+	}
+
+	for _, tt := range tests {
+
+		var buf bytes.Buffer
+		archive := zip.NewWriter(&buf)
+
+		for name, contents := range tt.files {
+			w, _ := archive.Create(name)
+			io.WriteString(w, contents)
+		}
+
+		archive.Close()
+
+		if _, err := ReadPackage(bytes.NewReader(buf.Bytes()), int64(buf.Len())); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+
+	if _, err := ReadPackage(bytes.NewReader([]byte("text")), 4); err == nil {
+		t.Error("Expected an error for a file that is not a zip archive")
+	}
+}
@@ -0,0 +1,512 @@
+package anki
+
+import (
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"io"
+	"slices"
+)
+
+// The SQLite file format is described at https://www.sqlite.org/fileformat2.html.
+// database writes only what an Anki package needs: tables with integer rowids and
+// indexes on integer columns, built once and never updated
+
+// pageSize is the size of the pages of the database file
+const pageSize = 4096
+
+// Page types of the b-tree pages
+const (
+	interiorIndex = 0x02
+	interiorTable = 0x05
+	leafIndex     = 0x0A
+	leafTable     = 0x0D
+)
+
+// row is a table row: its rowid and the values of the columns, each nil, int64 or string
+type row struct {
+	id     int64
+	values []any
+}
+
+// masterEntry is a row of the sqlite_master table that describes the schema
+type masterEntry struct {
+	kind  string // "table" or "index"
+	name  string
+	table string
+	root  int
+	sql   string
+}
+
+// database builds a SQLite database file in memory
+type database struct {
+	pages  [][]byte // pages[0] is page 1, which holds the file header and sqlite_master
+	master []masterEntry
+}
+
+// newDatabase creates an empty database
+func newDatabase() *database {
+	return &database{pages: [][]byte{make([]byte, pageSize)}}
+}
+
+// newPage adds an empty page and returns its number
+func (db *database) newPage() int {
+
+	db.pages = append(db.pages, make([]byte, pageSize))
+
+	return len(db.pages)
+}
+
+// page returns the contents of the page with the given number
+func (db *database) page(n int) []byte {
+	return db.pages[n-1]
+}
+
+// createTable adds a table with the given rows, which must be sorted by rowid
+func (db *database) createTable(name, sql string, rows []row) {
+
+	cells := make([]tableCell, len(rows))
+
+	for i, r := range rows {
+		cells[i] = tableCell{key: r.id, data: db.leafTableCell(r.id, encodeRecord(r.values))}
+	}
+
+	db.master = append(db.master, masterEntry{
+		kind:  "table",
+		name:  name,
+		table: name,
+		root:  db.buildTable(cells),
+		sql:   sql,
+	})
+}
+
+// createIndex adds an index on the integer columns of rows given by columns
+func (db *database) createIndex(name, table, sql string, rows []row, columns ...int) {
+
+	keys := make([][]int64, len(rows))
+
+	for i, r := range rows {
+		key := make([]int64, 0, len(columns)+1)
+		for _, c := range columns {
+			key = append(key, r.values[c].(int64))
+		}
+		keys[i] = append(key, r.id)
+	}
+
+	slices.SortFunc(keys, slices.Compare)
+
+	entries := make([][]byte, len(keys))
+
+	for i, key := range keys {
+		values := make([]any, len(key))
+		for j, v := range key {
+			values[j] = v
+		}
+		entries[i] = encodeRecord(values)
+	}
+
+	db.master = append(db.master, masterEntry{
+		kind:  "index",
+		name:  name,
+		table: table,
+		root:  db.buildIndex(entries),
+		sql:   sql,
+	})
+}
+
+// WriteTo writes the database file with the schema in sqlite_master on page 1
+func (db *database) WriteTo(w io.Writer) (int64, error) {
+
+	cells := make([][]byte, len(db.master))
+
+	for i, m := range db.master {
+		record := encodeRecord([]any{m.kind, m.name, m.table, int64(m.root), m.sql})
+		cells[i] = db.leafTableCell(int64(i+1), record)
+	}
+
+	if !fits(100, leafTable, cells) {
+		return 0, errors.New("the schema does not fit on the first page")
+	}
+
+	page := db.page(1)
+	writePage(page, 100, leafTable, cells, 0)
+	db.writeHeader(page)
+
+	var n int64
+
+	for _, p := range db.pages {
+		written, err := w.Write(p)
+		n += int64(written)
+		if err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+// writeHeader writes the 100 byte database header to page 1
+func (db *database) writeHeader(page []byte) {
+
+	copy(page, "SQLite format 3\x00")
+	binary.BigEndian.PutUint16(page[16:], pageSize)
+	page[18], page[19] = 1, 1 // legacy journal mode
+	page[20] = 0              // no reserved bytes at the end of pages
+	page[21], page[22], page[23] = 64, 32, 32
+	binary.BigEndian.PutUint32(page[24:], 1) // file change counter
+	binary.BigEndian.PutUint32(page[28:], uint32(len(db.pages)))
+	binary.BigEndian.PutUint32(page[40:], 1) // schema cookie
+	binary.BigEndian.PutUint32(page[44:], 4) // schema format
+	binary.BigEndian.PutUint32(page[56:], 1) // UTF-8
+	binary.BigEndian.PutUint32(page[92:], 1) // the page count is valid for change 1
+	binary.BigEndian.PutUint32(page[96:], 3045000)
+}
+
+// tableCell is a cell of a table leaf page with its rowid
+type tableCell struct {
+	key  int64
+	data []byte
+}
+
+// buildTable writes the b-tree of a table with the given cells and returns its root page
+func (db *database) buildTable(cells []tableCell) int {
+
+	type child struct {
+		page int
+		key  int64
+	}
+
+	var level []child
+
+	// Leaf pages are filled in order, a cell always fits an empty page
+	for start := 0; start < len(cells) || level == nil; {
+
+		end := start
+		data := [][]byte{}
+
+		for end < len(cells) && fits(0, leafTable, append(data, cells[end].data)) {
+			data = append(data, cells[end].data)
+			end++
+		}
+
+		n := db.newPage()
+		writePage(db.page(n), 0, leafTable, data, 0)
+
+		key := int64(0)
+		if end > 0 {
+			key = cells[end-1].key
+		}
+
+		level = append(level, child{page: n, key: key})
+		start = end
+	}
+
+	// Every interior page holds one cell per child except the last, which is the right pointer
+	for len(level) > 1 {
+
+		var next []child
+
+		for start := 0; start < len(level); {
+
+			end := start + 1
+			data := [][]byte{}
+
+			for end < len(level) {
+
+				cell := interiorTableCell(level[end-1].page, level[end-1].key)
+				if !fits(0, interiorTable, append(data, cell)) {
+					break
+				}
+
+				data = append(data, cell)
+				end++
+			}
+
+			// A single child left over would need a page without cells
+			if end == len(level)-1 && len(data) > 1 {
+				end--
+				data = data[:len(data)-1]
+			}
+
+			n := db.newPage()
+			writePage(db.page(n), 0, interiorTable, data, uint32(level[end-1].page))
+
+			next = append(next, child{page: n, key: level[end-1].key})
+			start = end
+		}
+
+		level = next
+	}
+
+	return level[0].page
+}
+
+// buildIndex writes the b-tree of an index with the given sorted entries and returns
+// its root page. Unlike in tables, the entries of interior pages are not repeated in leaves
+func (db *database) buildIndex(entries [][]byte) int {
+
+	children, separators := db.indexLevel(entries, nil)
+
+	for len(children) > 1 {
+		children, separators = db.indexLevel(separators, children)
+	}
+
+	return children[0]
+}
+
+// indexLevel writes one level of an index b-tree. Without children it writes leaf pages
+// holding entries; otherwise entries[i] separates children[i] and children[i+1].
+// Returns the pages written and the entries that separate them on the level above
+func (db *database) indexLevel(entries [][]byte, children []int) ([]int, [][]byte) {
+
+	leaf := children == nil
+	kind := byte(interiorIndex)
+	if leaf {
+		kind = leafIndex
+	}
+
+	cell := func(i int) []byte {
+		if leaf {
+			return indexCell(0, entries[i], leafIndex)
+		}
+		return indexCell(children[i], entries[i], interiorIndex)
+	}
+
+	var pages []int
+	var separators [][]byte
+
+	for start := 0; ; {
+
+		end := start
+		data := [][]byte{}
+
+		for end < len(entries) && fits(0, kind, append(data, cell(end))) {
+			data = append(data, cell(end))
+			end++
+		}
+
+		// The entry after a full page moves up. A page is never left empty after it:
+		// if it would be, the last entry of this page moves up instead
+		if end == len(entries)-1 && end > start+1 {
+			end--
+			data = data[:len(data)-1]
+		}
+
+		right := uint32(0)
+		if !leaf {
+			right = uint32(children[end])
+		}
+
+		n := db.newPage()
+		writePage(db.page(n), 0, kind, data, right)
+		pages = append(pages, n)
+
+		if end >= len(entries) {
+			return pages, separators
+		}
+
+		separators = append(separators, entries[end])
+		start = end + 1
+	}
+}
+
+// leafTableCell returns a cell of a table leaf page holding record under rowid id
+func (db *database) leafTableCell(id int64, record []byte) []byte {
+
+	cell := putVarint(nil, uint64(len(record)))
+	cell = putVarint(cell, uint64(id))
+
+	return db.appendPayload(cell, record, pageSize-35)
+}
+
+// interiorTableCell returns a cell of an interior table page pointing to child
+// whose largest rowid is key
+func interiorTableCell(child int, key int64) []byte {
+
+	cell := binary.BigEndian.AppendUint32(nil, uint32(child))
+
+	return putVarint(cell, uint64(key))
+}
+
+// indexCell returns a cell of an index page holding the record of an entry.
+// Interior cells start with the page of the entries before it. Entries of integers
+// are short, so they never need overflow pages
+func indexCell(child int, record []byte, kind byte) []byte {
+
+	var cell []byte
+	if kind == interiorIndex {
+		cell = binary.BigEndian.AppendUint32(cell, uint32(child))
+	}
+
+	cell = putVarint(cell, uint64(len(record)))
+
+	return append(cell, record...)
+}
+
+// appendPayload appends payload to a cell, moving what does not fit in maxLocal
+// bytes to overflow pages
+func (db *database) appendPayload(cell, payload []byte, maxLocal int) []byte {
+
+	if len(payload) <= maxLocal {
+		return append(cell, payload...)
+	}
+
+	minLocal := (pageSize-12)*32/255 - 23
+	local := minLocal + (len(payload)-minLocal)%(pageSize-4)
+	if local > maxLocal {
+		local = minLocal
+	}
+
+	cell = append(cell, payload[:local]...)
+	rest := payload[local:]
+
+	first := db.newPage()
+	cell = binary.BigEndian.AppendUint32(cell, uint32(first))
+
+	for n := first; len(rest) > 0; {
+
+		page := db.page(n)
+		size := copy(page[4:], rest)
+		rest = rest[size:]
+
+		if len(rest) > 0 {
+			next := db.newPage()
+			binary.BigEndian.PutUint32(page, uint32(next))
+			n = next
+		}
+	}
+
+	return cell
+}
+
+// headerSize returns the size of the header of a b-tree page of the given kind
+func headerSize(kind byte) int {
+
+	if kind == interiorIndex || kind == interiorTable {
+		return 12
+	}
+
+	return 8
+}
+
+// fits reports whether cells fit on a page whose b-tree header starts at offset
+func fits(offset int, kind byte, cells [][]byte) bool {
+
+	size := offset + headerSize(kind)
+	for _, c := range cells {
+		size += len(c) + 2
+	}
+
+	return size <= pageSize
+}
+
+// writePage writes a b-tree page with its header at offset and the cells at the end of the page
+func writePage(page []byte, offset int, kind byte, cells [][]byte, right uint32) {
+
+	content := pageSize
+	pointers := offset + headerSize(kind)
+
+	for i, c := range cells {
+		content -= len(c)
+		copy(page[content:], c)
+		binary.BigEndian.PutUint16(page[pointers+2*i:], uint16(content))
+	}
+
+	page[offset] = kind
+	binary.BigEndian.PutUint16(page[offset+1:], 0) // no free blocks
+	binary.BigEndian.PutUint16(page[offset+3:], uint16(len(cells)))
+	binary.BigEndian.PutUint16(page[offset+5:], uint16(content))
+	page[offset+7] = 0
+
+	if headerSize(kind) == 12 {
+		binary.BigEndian.PutUint32(page[offset+8:], right)
+	}
+}
+
+// encodeRecord encodes values in the SQLite record format
+func encodeRecord(values []any) []byte {
+
+	var types, body []byte
+
+	for _, v := range values {
+		switch v := v.(type) {
+		case nil:
+			types = putVarint(types, 0)
+		case int64:
+			serial, size := integerType(v)
+			types = putVarint(types, serial)
+			for i := size - 1; i >= 0; i-- {
+				body = append(body, byte(v>>(8*i)))
+			}
+		case string:
+			types = putVarint(types, uint64(len(v))*2+13)
+			body = append(body, v...)
+		}
+	}
+
+	// The header size includes its own varint, which is one byte for any record here
+	header := putVarint(nil, uint64(len(types)+1))
+	if len(header) > 1 {
+		header = putVarint(nil, uint64(len(types)+2))
+	}
+
+	return append(append(header, types...), body...)
+}
+
+// integerType returns the serial type and size of the smallest encoding of v
+func integerType(v int64) (uint64, int) {
+
+	switch {
+	case v >= -1<<7 && v < 1<<7:
+		return 1, 1
+	case v >= -1<<15 && v < 1<<15:
+		return 2, 2
+	case v >= -1<<23 && v < 1<<23:
+		return 3, 3
+	case v >= -1<<31 && v < 1<<31:
+		return 4, 4
+	case v >= -1<<47 && v < 1<<47:
+		return 5, 6
+	default:
+		return 6, 8
+	}
+}
+
+// putVarint appends v as a SQLite variable length integer
+func putVarint(buf []byte, v uint64) []byte {
+
+	if v > 1<<56-1 {
+
+		var b [9]byte
+		b[8] = byte(v)
+		v >>= 8
+
+		for i := 7; i >= 0; i-- {
+			b[i] = byte(v&0x7f) | 0x80
+			v >>= 7
+		}
+
+		return append(buf, b[:]...)
+	}
+
+	var b [8]byte
+	n := len(b)
+
+	for {
+		n--
+		b[n] = byte(v&0x7f) | 0x80
+		v >>= 7
+		if v == 0 {
+			break
+		}
+	}
+
+	b[len(b)-1] &= 0x7f
+
+	return append(buf, b[n:]...)
+}
+
+// compareRows orders rows by rowid
+func compareRows(a, b row) int {
+	return cmp.Compare(a.id, b.id)
+}
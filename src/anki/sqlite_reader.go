@@ -0,0 +1,337 @@
+package anki
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// errMalformed is returned for database files whose structure cannot be read
+var errMalformed = errors.New("the database file is malformed")
+
+// maxDepth limits the depth of the b-trees read, so that a page referring to itself
+// cannot make reading loop forever
+const maxDepth = 64
+
+// databaseFile reads the tables of a SQLite database file held in memory
+type databaseFile struct {
+	data   []byte
+	size   int // page size
+	usable int // bytes of a page not reserved for extensions
+}
+
+// openDatabaseFile checks the header of a database file
+func openDatabaseFile(data []byte) (*databaseFile, error) {
+
+	if len(data) < 100 || string(data[:16]) != "SQLite format 3\x00" {
+		return nil, errors.New("not a SQLite database")
+	}
+
+	size := int(binary.BigEndian.Uint16(data[16:]))
+	if size == 1 {
+		size = 65536
+	}
+
+	if size < 512 || size&(size-1) != 0 {
+		return nil, errMalformed
+	}
+
+	if encoding := binary.BigEndian.Uint32(data[56:]); encoding > 1 {
+		return nil, errors.New("only UTF-8 databases are supported")
+	}
+
+	return &databaseFile{data: data, size: size, usable: size - int(data[20])}, nil
+}
+
+// page returns the contents of the page with the given number
+func (f *databaseFile) page(n uint32) ([]byte, error) {
+
+	start := (int(n) - 1) * f.size
+	if n == 0 || start+f.size > len(f.data) {
+		return nil, errMalformed
+	}
+
+	return f.data[start : start+f.size], nil
+}
+
+// rootPages returns the root pages of the tables by name, read from sqlite_master
+func (f *databaseFile) rootPages() (map[string]uint32, error) {
+
+	roots := make(map[string]uint32)
+
+	err := f.scan(1, func(id int64, values []any) error {
+
+		if len(values) < 4 {
+			return errMalformed
+		}
+
+		kind, _ := values[0].(string)
+		name, _ := values[1].(string)
+		root, _ := values[3].(int64)
+
+		if kind == "table" {
+			roots[name] = uint32(root)
+		}
+
+		return nil
+	})
+
+	return roots, err
+}
+
+// scanTable calls f for every row of the named table in the order of the rowids.
+// The value of an INTEGER PRIMARY KEY column is the rowid passed to f
+func (f *databaseFile) scanTable(name string, fn func(id int64, values []any) error) error {
+
+	roots, err := f.rootPages()
+	if err != nil {
+		return err
+	}
+
+	root, ok := roots[name]
+	if !ok {
+		return errors.New("the database has no table " + name)
+	}
+
+	return f.scan(root, fn)
+}
+
+// scan calls fn for every row of the table b-tree with the given root page
+func (f *databaseFile) scan(root uint32, fn func(id int64, values []any) error) error {
+	return f.scanPage(root, 0, fn)
+}
+
+// scanPage calls fn for every row of the subtree at page n
+func (f *databaseFile) scanPage(n uint32, depth int, fn func(id int64, values []any) error) error {
+
+	if depth > maxDepth {
+		return errMalformed
+	}
+
+	page, err := f.page(n)
+	if err != nil {
+		return err
+	}
+
+	offset := 0
+	if n == 1 {
+		offset = 100
+	}
+
+	kind := page[offset]
+	if kind != leafTable && kind != interiorTable {
+		return errMalformed
+	}
+
+	count := int(binary.BigEndian.Uint16(page[offset+3:]))
+	pointers := offset + headerSize(kind)
+
+	if pointers+2*count > f.usable {
+		return errMalformed
+	}
+
+	for i := range count {
+
+		cell := int(binary.BigEndian.Uint16(page[pointers+2*i:]))
+		if cell >= f.usable {
+			return errMalformed
+		}
+
+		if kind == interiorTable {
+
+			if cell+4 > f.usable {
+				return errMalformed
+			}
+
+			if err := f.scanPage(binary.BigEndian.Uint32(page[cell:]), depth+1, fn); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		id, values, err := f.readLeafCell(page[:f.usable], cell)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(id, values); err != nil {
+			return err
+		}
+	}
+
+	if kind == interiorTable {
+		return f.scanPage(binary.BigEndian.Uint32(page[offset+8:]), depth+1, fn)
+	}
+
+	return nil
+}
+
+// readLeafCell reads the rowid and the record of the table leaf cell at offset of page,
+// following its overflow pages
+func (f *databaseFile) readLeafCell(page []byte, offset int) (int64, []any, error) {
+
+	size, n := getVarint(page[offset:])
+	if n == 0 {
+		return 0, nil, errMalformed
+	}
+	offset += n
+
+	id, n := getVarint(page[offset:])
+	if n == 0 {
+		return 0, nil, errMalformed
+	}
+	offset += n
+
+	if size > uint64(len(f.data)) {
+		return 0, nil, errMalformed
+	}
+
+	local := localPayload(int(size), f.usable)
+	if offset+local > len(page) {
+		return 0, nil, errMalformed
+	}
+
+	payload := page[offset : offset+local]
+
+	if local < int(size) {
+
+		if offset+local+4 > len(page) {
+			return 0, nil, errMalformed
+		}
+
+		payload = append([]byte(nil), payload...)
+		next := binary.BigEndian.Uint32(page[offset+local:])
+
+		for len(payload) < int(size) {
+
+			overflow, err := f.page(next)
+			if err != nil {
+				return 0, nil, err
+			}
+
+			next = binary.BigEndian.Uint32(overflow)
+			payload = append(payload, overflow[4:min(f.usable, 4+int(size)-len(payload))]...)
+		}
+	}
+
+	values, err := decodeRecord(payload)
+
+	return int64(id), values, err
+}
+
+// localPayload returns how many bytes of a table leaf payload of the given size are
+// stored in the cell, the rest being stored on overflow pages
+func localPayload(size, usable int) int {
+
+	maxLocal := usable - 35
+	if size <= maxLocal {
+		return size
+	}
+
+	minLocal := (usable-12)*32/255 - 23
+	local := minLocal + (size-minLocal)%(usable-4)
+	if local > maxLocal {
+		local = minLocal
+	}
+
+	return local
+}
+
+// decodeRecord decodes the values of a record: nil, int64, float64, string or []byte
+func decodeRecord(record []byte) ([]any, error) {
+
+	headerLen, n := getVarint(record)
+	if n == 0 || headerLen > uint64(len(record)) {
+		return nil, errMalformed
+	}
+
+	header := record[n:headerLen]
+	body := record[headerLen:]
+	var values []any
+
+	for len(header) > 0 {
+
+		serial, n := getVarint(header)
+		if n == 0 {
+			return nil, errMalformed
+		}
+		header = header[n:]
+
+		size := serialSize(serial)
+		if size < 0 || size > len(body) {
+			return nil, errMalformed
+		}
+
+		field := body[:size]
+		body = body[size:]
+
+		switch {
+		case serial == 0:
+			values = append(values, nil)
+		case serial <= 6:
+			v := int64(int8(field[0]))
+			for _, b := range field[1:] {
+				v = v<<8 | int64(b)
+			}
+			values = append(values, v)
+		case serial == 7:
+			values = append(values, math.Float64frombits(binary.BigEndian.Uint64(field)))
+		case serial == 8, serial == 9:
+			values = append(values, int64(serial-8))
+		case serial%2 == 0:
+			values = append(values, append([]byte(nil), field...))
+		default:
+			values = append(values, string(field))
+		}
+	}
+
+	return values, nil
+}
+
+// serialSize returns the size of a value with the given serial type, or -1 if the
+// serial type is reserved
+func serialSize(serial uint64) int {
+
+	switch {
+	case serial <= 4:
+		return int(serial)
+	case serial == 5:
+		return 6
+	case serial <= 7:
+		return 8
+	case serial <= 9:
+		return 0
+	case serial <= 11:
+		return -1
+	case serial > math.MaxInt32:
+		return -1
+	default:
+		return int(serial-12) / 2
+	}
+}
+
+// getVarint decodes a SQLite varint. Returns the value and the number of bytes read,
+// 0 if buf ends before the varint does
+func getVarint(buf []byte) (uint64, int) {
+
+	var v uint64
+
+	for i := 0; i < 9; i++ {
+
+		if i == len(buf) {
+			return 0, 0
+		}
+
+		if i == 8 {
+			return v<<8 | uint64(buf[i]), 9
+		}
+
+		v = v<<7 | uint64(buf[i]&0x7f)
+		if buf[i] < 0x80 {
+			return v, i + 1
+		}
+	}
+
+	return v, 9
+}
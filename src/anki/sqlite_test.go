@@ -0,0 +1,201 @@
+package anki
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// sqlite3 runs a query with the sqlite3 command line tool, skipping the test without it
+func sqlite3(t *testing.T, file, query string) string {
+
+	t.Helper()
+
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 is not installed")
+	}
+
+	out, err := exec.Command("sqlite3", file, query).CombinedOutput()
+	if err != nil {
+		t.Fatalf("sqlite3 %q: %v\n%s", query, err, out)
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+func TestPutVarint(t *testing.T) {
+
+	tests := []struct {
+		v        uint64
+		expected []byte
+	}{
+		{0, []byte{0x00}},
+		{127, []byte{0x7f}},
+		{128, []byte{0x81, 0x00}},
+		{16383, []byte{0xff, 0x7f}},
+		{1<<56 - 1, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f}},
+		{1 << 63, []byte{0xc0, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00}},
+	}
+
+	for _, tt := range tests {
+		if got := putVarint(nil, tt.v); !bytes.Equal(got, tt.expected) {
+			t.Errorf("putVarint(%d) = %x, want %x", tt.v, got, tt.expected)
+		}
+	}
+}
+
+func TestDatabase_sqlite3(t *testing.T) {
+
+	db := newDatabase()
+	rows := make([]row, 3000)
+
+	for i := range rows {
+
+		text := fmt.Sprintf("row %d", i)
+		if i%500 == 0 {
+			text = strings.Repeat("long ", 3000+i)
+		}
+
+		rows[i] = row{id: int64(i*7 + 1), values: []any{nil, text, int64(i % 13), int64(-i * 100000)}}
+	}
+
+	db.createTable("t", "CREATE TABLE t (id integer primary key, s text, n integer, m integer)", rows)
+	db.createIndex("ix_t_n", "t", "CREATE INDEX ix_t_n on t (n, m)", rows, 2, 3)
+	db.createTable("empty", "CREATE TABLE empty (a integer)", nil)
+	db.createIndex("ix_empty", "empty", "CREATE INDEX ix_empty on empty (a)", nil)
+
+	file := filepath.Join(t.TempDir(), "test.db")
+
+	var buf bytes.Buffer
+	if _, err := db.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(file, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if out := sqlite3(t, file, "PRAGMA integrity_check"); out != "ok" {
+		t.Fatalf("integrity_check: %s", out)
+	}
+
+	if out := sqlite3(t, file, "SELECT count(*), sum(length(s)), max(id) FROM t"); out != fmt.Sprintf("3000|%d|20994", expectedLength(rows)) {
+		t.Errorf("Unexpected table contents: %s", out)
+	}
+
+	if out := sqlite3(t, file, "SELECT id FROM t INDEXED BY ix_t_n WHERE n = 5 AND m = -500000"); out != "36" {
+		t.Errorf("Unexpected index lookup: %s", out)
+	}
+}
+
+func expectedLength(rows []row) int {
+
+	n := 0
+	for _, r := range rows {
+		n += len(r.values[1].(string))
+	}
+
+	return n
+}
+
+func TestDatabaseFile_roundTrip(t *testing.T) {
+
+	db := newDatabase()
+	rows := make([]row, 2000)
+
+	for i := range rows {
+		rows[i] = row{id: int64(i + 1), values: []any{nil, strings.Repeat("ё", i*5), int64(i - 1000)}}
+	}
+
+	db.createTable("t", "CREATE TABLE t (id integer primary key, s text, n integer)", rows)
+
+	var buf bytes.Buffer
+	if _, err := db.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := openDatabaseFile(buf.Bytes())
+	if err != nil {
+		t.Fatalf("openDatabaseFile: %v", err)
+	}
+
+	var read []row
+	err = file.scanTable("t", func(id int64, values []any) error {
+		read = append(read, row{id: id, values: values})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("scanTable: %v", err)
+	}
+
+	if !reflect.DeepEqual(read, rows) {
+		t.Errorf("Read %d rows that differ from the %d written", len(read), len(rows))
+	}
+
+	if err := file.scanTable("missing", func(int64, []any) error { return nil }); err == nil {
+		t.Error("Expected an error for a missing table")
+	}
+}
+
+func TestDatabaseFile_sqlite3(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "test.db")
+
+	sqlite3(t, file, "PRAGMA page_size = 1024; CREATE TABLE t (a text, b real, c blob, d integer);"+
+		"WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 500) "+
+		"INSERT INTO t SELECT printf('%.*c', i * 7, 'x'), i / 2.0 + 0.25, x'00ff', NULL FROM n")
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := openDatabaseFile(data)
+	if err != nil {
+		t.Fatalf("openDatabaseFile: %v", err)
+	}
+
+	count := 0
+	err = db.scanTable("t", func(id int64, values []any) error {
+
+		count++
+		expected := []any{strings.Repeat("x", int(id)*7), float64(id)/2 + 0.25, []byte{0x00, 0xff}, nil}
+
+		if !reflect.DeepEqual(values, expected) {
+			return fmt.Errorf("row %d = %v", id, values)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 500 {
+		t.Errorf("Read %d rows, want 500", count)
+	}
+}
+
+func TestOpenDatabaseFile_invalid(t *testing.T) {
+
+	if _, err := openDatabaseFile([]byte("not a database")); err == nil {
+		t.Error("Expected an error for a file that is not a database")
+	}
+
+	// A database whose only page is cut short
+	data := append([]byte("SQLite format 3\x00\x10\x00"), make([]byte, 200)...)
+
+	db, err := openDatabaseFile(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.scanTable("t", func(int64, []any) error { return nil }); err == nil {
+		t.Error("Expected an error for a truncated database")
+	}
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Your-RoGr/DeckBuilder/src/appUtils"
+	"github.com/Your-RoGr/DeckBuilder/src/dataFrame"
+)
+
+// editEntry asks for new values of the selected entry, pre-filled with the current ones,
+// and writes them back to the deck
+func (v *rowView) editEntry() error {
+
+	deck, err := v.loadDeck()
+	if err != nil {
+		return err
+	}
+
+	index := v.index()
+	if index >= len(deck.Data) {
+		return nil
+	}
+
+	row := deck.Data[index]
+	values := make([]string, len(deck.Columns))
+
+	for i, column := range deck.Columns {
+
+		current := ""
+		if i < len(row) {
+			current = row[i]
+		}
+
+		// The first column identifies the entry and cannot be empty
+		value, ok := appUtils.GetInputWithValue(fmt.Sprintf("%s: ", column), current, i == 0)
+		if !ok {
+			return nil
+		}
+
+		values[i] = value
+	}
+
+	values = normalizeRow(deck.Columns, values, v.normalize)
+	if values[0] == "" {
+		return fmt.Errorf("%s cannot be empty", deck.Columns[0])
+	}
+
+	err = deck.UpdateRowAndSave(index, values, v.path)
+
+	if errors.Is(err, dataFrame.ErrDuplicateKey) {
+		return fmt.Errorf("'%s' already exists!", values[0])
+	}
+
+	if err := v.resolveConflict(err); err != nil {
+		return err
+	}
+
+	v.status = fmt.Sprintf("%s - updated", strings.Join(values, " - "))
+
+	return v.reload()
+}
+
+// deleteEntry asks for confirmation and deletes the selected entry,
+// together with other entries with the same value in the first column
+func (v *rowView) deleteEntry() error {
+
+	deck, err := v.loadDeck()
+	if err != nil {
+		return err
+	}
+
+	index := v.index()
+	if index >= len(deck.Data) || len(deck.Data[index]) == 0 {
+		return nil
+	}
+
+	column := deck.Columns[0]
+	value := deck.Data[index][0]
+
+	matches, err := deck.Find(column, value)
+	if err != nil {
+		return err
+	}
+
+	input, ok := appUtils.GetInput(
+		fmt.Sprintf("Delete %d entry(s) '%s'? (y)", len(matches), value),
+		true,
+	)
+
+	if !ok || input != "y" {
+		return nil
+	}
+
+	removed, err := deck.DeleteRowsWhereAndSave(column, dataFrame.Equals(value), v.path)
+	if err := v.resolveConflict(err); err != nil {
+		return err
+	}
+
+	v.status = fmt.Sprintf("%d entry(s) deleted", len(removed))
+
+	return v.reload()
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Your-RoGr/DeckBuilder/src/appUtils"
+	"github.com/Your-RoGr/DeckBuilder/src/exporter"
+)
+
+// exportUsage describes the arguments of the export command
+const exportUsage = "usage: DeckBuilder export [-format json] [-o file] deck.csv"
+
+// RunCommand runs a command given on the command line without starting the menu.
+// Returns false if args do not start with a known command
+func RunCommand(args []string, stdout io.Writer) (bool, error) {
+
+	if len(args) == 0 {
+		return false, nil
+	}
+
+	switch args[0] {
+	case "export":
+		return true, exportCommand(args[1:], stdout)
+	default:
+		return false, nil
+	}
+}
+
+// exportCommand exports a deck to a file, or to stdout if no file is given
+func exportCommand(args []string, stdout io.Writer) error {
+
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	formatName := flags.String("format", exporter.Names()[0], fmt.Sprintf("export format (%s)", strings.Join(exporter.Names(), ", ")))
+	output := flags.String("o", "", "output file, the standard output if not set")
+
+	err := flags.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return errors.New(exportUsage)
+	}
+
+	format, err := exporter.Lookup(*formatName)
+	if err != nil {
+		return err
+	}
+
+	path := flags.Arg(0)
+
+	df, err := openDeck(path)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return exporter.Export(stdout, df, format, exporter.Title(path))
+	}
+
+	return exporter.ExportFile(*output, df, format, exporter.Title(path))
+}
+
+// exportDeck asks for a format and a file name and exports the deck at path
+func exportDeck(path string) error {
+
+	names := exporter.Names()
+
+	name, ok := appUtils.GetInputWithValue(fmt.Sprintf("Export format (%s): ", strings.Join(names, ", ")), names[0], true)
+	if !ok {
+		return nil
+	}
+
+	format, err := exporter.Lookup(strings.TrimSpace(name))
+	if err != nil {
+		return err
+	}
+
+	output, ok := appUtils.GetInputWithValue("Export to: ", exporter.OutputPath(path, format), true)
+	if !ok {
+		return nil
+	}
+
+	if _, err := os.Stat(output); err == nil {
+
+		input, ok := appUtils.GetInput(fmt.Sprintf("%s already exists, overwrite? (y)", output), true)
+		if !ok || input != "y" {
+			return nil
+		}
+	}
+
+	df, err := openDeck(path)
+	if err != nil {
+		return err
+	}
+
+	if err := exporter.ExportFile(output, df, format, exporter.Title(path)); err != nil {
+		return err
+	}
+
+	appUtils.PrintHotkeyBar(fmt.Sprintf("%s - exported", output), true)
+
+	return nil
+}
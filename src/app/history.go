@@ -0,0 +1,92 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/Your-RoGr/DeckBuilder/src/appUtils"
+	"github.com/Your-RoGr/DeckBuilder/src/dataFrame"
+)
+
+// undoesCatalog reports whether Ctrl+Z/Y in m undo changes of the catalog. The menu of
+// the modes (Word, Show, ...) changes neither the catalog nor a deck, so it has no undo;
+// changes of a deck are undone where they are made (Show, Word)
+func (m *Menu) undoesCatalog() bool {
+	return m.name != "Select file from catalog"
+}
+
+// undo reverts the last change of the catalog, or repeats the last undone change if redo is true
+func (m *Menu) undo(redo bool) error {
+
+	var message string
+
+	err := m.changeCatalog(func(df *dataFrame.DataFrame) (err error) {
+		message, err = undoAndSave(df, existFilesPath, redo)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	m.refresh()
+	appUtils.PrintHotkeyBar(message, true)
+
+	return nil
+}
+
+// undo reverts the last change of the deck, or repeats the last undone change if redo is true
+func (v *rowView) undo(redo bool) error {
+
+	deck, err := v.loadDeck()
+	if err != nil {
+		return err
+	}
+
+	message, err := undoAndSave(deck, v.path, redo)
+	if err := v.resolveConflict(err); err != nil {
+		return err
+	}
+
+	v.status = message
+
+	return v.reload()
+}
+
+// undoAndSave undoes or redoes the last change of df and saves it to path.
+// Returns a message describing the result, also when only the save failed
+func undoAndSave(df *dataFrame.DataFrame, path string, redo bool) (string, error) {
+
+	var ok bool
+	var err error
+	action := "undo"
+
+	if redo {
+		ok, err = df.RedoAndSave(path)
+		action = "redo"
+	} else {
+		ok, err = df.UndoAndSave(path)
+	}
+
+	if !ok && err == nil {
+		return fmt.Sprintf("Nothing to %s", action), nil
+	}
+
+	return fmt.Sprintf("%s done", action), err
+}
+
+// refresh rebuilds the options of menus that list catalog files
+func (m *Menu) refresh() {
+
+	if m.parent == nil || m.parent.name != "Select file from catalog" {
+		return
+	}
+
+	m.options, _ = m.catalog.Column(catalogColumn)
+
+	if m.selected >= len(m.options) {
+		m.selected = len(m.options) - 1
+	}
+
+	if m.selected < 0 {
+		m.selected = 0
+	}
+}
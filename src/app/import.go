@@ -0,0 +1,350 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Your-RoGr/DeckBuilder/src/appUtils"
+	"github.com/Your-RoGr/DeckBuilder/src/dataFrame"
+	"github.com/Your-RoGr/DeckBuilder/src/deckConfig"
+	"github.com/Your-RoGr/DeckBuilder/src/fileUtils"
+	"github.com/Your-RoGr/DeckBuilder/src/importer"
+)
+
+// previewRows is the number of imported entries shown before importing
+const previewRows = 100
+
+// importDeck asks for a file and its format, shows how its fields map to the columns
+// of the deck at path, and adds the entries that the deck does not have yet
+func importDeck(path string) error {
+
+	source, ok := appUtils.GetInput("Import from: ", true)
+	if !ok {
+		return nil
+	}
+
+	source = strings.TrimSpace(source)
+
+	detected, err := importer.Detect(source)
+	if err != nil {
+		return err
+	}
+
+	name, ok := appUtils.GetInputWithValue(
+		fmt.Sprintf("Import format (%s): ", strings.Join(importer.Names(), ", ")),
+		detected.Name,
+		true,
+	)
+	if !ok {
+		return nil
+	}
+
+	format, err := importer.Lookup(strings.TrimSpace(name))
+	if err != nil {
+		return err
+	}
+
+	options, ok := importOptions(format)
+	if !ok {
+		return nil
+	}
+
+	table, err := importer.ReadFile(source, format, options)
+	if err != nil {
+		return err
+	}
+
+	config, err := deckConfig.Open(path)
+	if err != nil {
+		return err
+	}
+
+	lock, err := fileUtils.LockDeck(config)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	deck, err := openDeck(path)
+	if err != nil {
+		return err
+	}
+
+	mapping := importer.MapColumns(deck.Columns, table.Columns)
+	rows := importedRows(deck.Columns, mapping, table.Data, config)
+
+	title := fmt.Sprintf("Import %d entry(s) from %s into %s? Enter - import", len(rows), source, path)
+	if !appUtils.ShowLines(title, previewLines(deck.Columns, table.Columns, mapping, rows)) {
+		return nil
+	}
+
+	added, err := importRows(deck, path, rows)
+	if err != nil {
+		return err
+	}
+
+	appUtils.PrintHotkeyBar(fmt.Sprintf("%d entry(s) added, %d skipped", added, len(rows)-added), true)
+
+	return nil
+}
+
+// importPackage creates a deck from the notes of an Anki package and adds it to the catalog.
+// If the import fails after the deck file is created, the file and its settings are removed
+func (m *Menu) importPackage() (err error) {
+
+	source, ok := appUtils.GetInput("Anki package (.apkg, .colpkg): ", true)
+	if !ok {
+		return nil
+	}
+
+	source = strings.TrimSpace(source)
+
+	format, err := importer.Lookup("apkg")
+	if err != nil {
+		return err
+	}
+
+	options, ok := importOptions(format)
+	if !ok {
+		return nil
+	}
+
+	table, err := importer.ReadFile(source, format, options)
+	if err != nil {
+		return err
+	}
+
+	path, ok := appUtils.GetInputWithValue("New deck: ", strings.TrimSuffix(source, filepath.Ext(source))+".csv", true)
+	if !ok {
+		return nil
+	}
+
+	path, err = filepath.Abs(strings.TrimSpace(path))
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	}
+
+	config, err := deckConfig.Open(path)
+	if err != nil {
+		return err
+	}
+
+	columns, mapping, extra := packageColumns(table.Columns)
+	rows := importedRows(columns, mapping, table.Data, config)
+
+	title := fmt.Sprintf("Create %s with %d entry(s) from %s? Enter - create", path, len(rows), source)
+	if !appUtils.ShowLines(title, previewLines(columns, table.Columns, mapping, rows)) {
+		return nil
+	}
+
+	if err := config.Set(deckConfig.KeyFields, strings.Join(extra, ",")); err != nil {
+		return err
+	}
+
+	if err := dataFrame.CreateNewCSV(path, columns, ';'); err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, removeDeck(path, config))
+		}
+	}()
+
+	if err := config.Save(); err != nil {
+		return err
+	}
+
+	deck, err := openDeck(path)
+	if err != nil {
+		return err
+	}
+
+	added, err := importRows(deck, path, rows)
+	if err != nil {
+		return err
+	}
+
+	err = m.changeCatalog(func(df *dataFrame.DataFrame) error {
+		return df.AddRowAndSave([]string{path}, existFilesPath)
+	})
+	if err != nil {
+		return err
+	}
+
+	appUtils.PrintHotkeyBar(fmt.Sprintf("%s - %d entry(s) imported", path, added), true)
+
+	return nil
+}
+
+// removeDeck removes the deck file at path created by a failed import,
+// together with its settings and undo history
+func removeDeck(path string, config *deckConfig.Config) error {
+
+	history := os.Remove(deckConfig.HistoryPath(path))
+	if errors.Is(history, os.ErrNotExist) {
+		history = nil
+	}
+
+	return errors.Join(os.Remove(path), config.Delete(), history)
+}
+
+// packageColumns returns the columns of a deck created from a package with the given
+// fields and their mapping: Word, Translation, then the fields that have no deck column,
+// then Tags and Deck. Also returns the names of the fields that became extra columns
+func packageColumns(fields []string) ([]string, importer.Mapping, []string) {
+
+	base := slices.Concat(fileUtils.DeckColumns, fileUtils.OptionalDeckColumns)
+	extra := importer.MapColumns(base, fields).Unmapped(fields)
+
+	columns := slices.Concat(fileUtils.DeckColumns, extra, fileUtils.OptionalDeckColumns)
+	mapping := importer.MapColumns(columns, fields)
+
+	// Commas separate the extra columns in the deck settings
+	for i, name := range extra {
+		extra[i] = strings.ReplaceAll(name, ",", " ")
+		columns[len(fileUtils.DeckColumns)+i] = extra[i]
+	}
+
+	return columns, mapping, extra
+}
+
+// importOptions asks for the options of format, if it has any.
+// Returns false if the import was cancelled
+func importOptions(format importer.Format) (importer.Options, bool) {
+
+	var options importer.Options
+
+	switch format.Name {
+	case "txt":
+
+		separator, ok := appUtils.GetInputWithValue("Separator of the fields (e.g. ' - ', '=', tab): ", " - ", true)
+		if !ok {
+			return options, false
+		}
+
+		if strings.EqualFold(strings.TrimSpace(separator), "tab") {
+			separator = "\t"
+		}
+
+		options.Separator = separator
+	case "apkg":
+
+		answer, ok := appUtils.GetInput("Keep the HTML formatting of the fields? (y/N): ", true)
+		if !ok {
+			return options, false
+		}
+
+		options.KeepHTML = strings.EqualFold(strings.TrimSpace(answer), "y")
+	}
+
+	return options, true
+}
+
+// importedRows maps the imported rows to the columns of the deck and normalizes them
+// as set for the deck. Entries without a value in the first column are dropped, and the
+// Tags and Deck columns get the defaults of the deck when the import has no value for them
+func importedRows(columns []string, mapping importer.Mapping, data [][]string, config *deckConfig.Config) [][]string {
+
+	rows := make([][]string, 0, len(data))
+	pipeline := config.Normalization()
+
+	for _, record := range data {
+
+		row := normalizeRow(columns, mapping.Row(record), pipeline)
+		if len(row) == 0 || row[0] == "" {
+			continue
+		}
+
+		for i, column := range columns {
+			switch {
+			case column == deckConfig.ColumnTags && row[i] == "":
+				row[i] = config.Tags()
+			case column == deckConfig.ColumnDeck && row[i] == "":
+				row[i] = config.Get(deckConfig.KeyDeck)
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// previewLines describes the mapping followed by the first imported entries
+func previewLines(columns, fields []string, mapping importer.Mapping, rows [][]string) []string {
+
+	lines := []string{"Columns:"}
+
+	for _, line := range mapping.Lines(columns, fields) {
+		lines = append(lines, "  "+line)
+	}
+
+	lines = append(lines, "", "Entries:")
+
+	for _, row := range rows[:min(len(rows), previewRows)] {
+		lines = append(lines, "  "+strings.Join(row, " - "))
+	}
+
+	if len(rows) > previewRows {
+		lines = append(lines, fmt.Sprintf("  ... and %d more", len(rows)-previewRows))
+	}
+
+	return lines
+}
+
+// importRows adds the rows the deck does not have yet and saves the deck once, so that
+// the whole import is undone at once. If the deck file was changed in the meantime,
+// it is loaded again and the rows are added to it. Returns the number of rows added
+func importRows(deck *dataFrame.DataFrame, path string, rows [][]string) (int, error) {
+
+	if err := deck.EnableIndex(); err != nil {
+		return 0, err
+	}
+
+	for retried := false; ; retried = true {
+
+		if err := deck.Begin(); err != nil {
+			return 0, err
+		}
+
+		before := len(deck.Data)
+
+		for _, row := range rows {
+			if err := deck.AddUniqueRow(row); err != nil {
+				return 0, errors.Join(err, deck.Rollback())
+			}
+		}
+
+		added := len(deck.Data) - before
+
+		err := deck.Commit(path)
+
+		if errors.Is(err, dataFrame.ErrFileChanged) && !retried {
+
+			if err := deck.Rollback(); err != nil {
+				return 0, err
+			}
+
+			if err := deck.LoadCSV(path); err != nil {
+				return 0, err
+			}
+
+			continue
+		}
+
+		if err != nil {
+			return 0, errors.Join(err, deck.Rollback())
+		}
+
+		return added, nil
+	}
+}
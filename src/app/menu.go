@@ -1,17 +1,24 @@
 package app
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/Your-RoGr/DeckBuilder/src/appUtils"
 	"github.com/Your-RoGr/DeckBuilder/src/dataFrame"
+	"github.com/Your-RoGr/DeckBuilder/src/deckConfig"
 	"github.com/Your-RoGr/DeckBuilder/src/fileUtils"
 	"github.com/nsf/termbox-go"
 )
 
 var existFilesPath = "~/.local/share/DeckBuilder/data/existFiles.csv"
-var dfFileOptions *dataFrame.DataFrame
+
+// catalogColumn is the column of the catalog that holds the paths of the decks
+const catalogColumn = "Option"
 
 type Menu struct {
 	name         string
@@ -21,26 +28,58 @@ type Menu struct {
 	menus        []*Menu
 	parent       *Menu
 	scrollOffset int
+	catalog      *dataFrame.SyncDataFrame // decks added to DeckBuilder, shared by all menus
+	catalogLoad  *catalogLoad             // result of loading the catalog, shared by all menus
+}
+
+// catalogLoad is the result of loading the catalog in the background
+type catalogLoad struct {
+	done <-chan error
+	once sync.Once
+	err  error
+}
+
+// wait waits for the catalog to be loaded and returns the error of loading it
+func (l *catalogLoad) wait() error {
+
+	if l == nil {
+		return nil
+	}
+
+	l.once.Do(func() {
+		if err := <-l.done; err != nil {
+			l.err = fmt.Errorf("the catalog %s cannot be loaded: %w", existFilesPath, err)
+		}
+	})
+
+	return l.err
 }
 
 // NewMenu создает новое меню с переданными опциями
 func NewMainMenu() *Menu {
 
-	err := dataFrame.CreateNewCSV(existFilesPath, []string{"Option"}, ';')
+	err := dataFrame.CreateNewCSV(existFilesPath, []string{catalogColumn}, ';')
 
 	if err != nil {
 		panic(err)
 	}
 
-	dfFileOptions = dataFrame.NewDataFrame(';')
-	dfFileOptions.LoadCSV(existFilesPath)
+	catalog := dataFrame.NewSyncDataFrame(';')
+
+	if journal, err := dataFrame.OpenJournal(deckConfig.HistoryPath(existFilesPath)); err == nil {
+		catalog.SetJournal(journal)
+	}
+
+	// The catalog is loaded while the main menu is shown
+	load := &catalogLoad{done: catalog.LoadCSVAsync(context.Background(), existFilesPath)}
 
 	options := []string{
 		"Select file from catalog",
 		"Select new file",
+		"Import Anki package",
 	}
 
-	menus := make([]*Menu, 2)
+	menus := make([]*Menu, 3)
 
 	return &Menu{
 		name:         "General",
@@ -50,6 +89,8 @@ func NewMainMenu() *Menu {
 		menus:        menus,
 		parent:       nil,
 		scrollOffset: 0,
+		catalog:      catalog,
+		catalogLoad:  load,
 	}
 }
 
@@ -65,6 +106,8 @@ func newSubMenu(name string, parent *Menu, options []string) *Menu {
 		menus:        menus,
 		parent:       parent,
 		scrollOffset: 0,
+		catalog:      parent.catalog,
+		catalogLoad:  parent.catalogLoad,
 	}
 }
 
@@ -97,6 +140,14 @@ func (m *Menu) Start() error {
 				if err != nil {
 					appUtils.GetInput(err.Error(), false)
 				}
+			case termbox.KeyCtrlZ, termbox.KeyCtrlY:
+				if !m.undoesCatalog() {
+					break
+				}
+
+				if err := m.undo(ev.Key == termbox.KeyCtrlY); err != nil {
+					appUtils.GetInput(err.Error(), false)
+				}
 			case termbox.KeyEsc:
 				m.running = false
 
@@ -114,14 +165,21 @@ func (m *Menu) Start() error {
 							)
 
 							if ok && input == "y" {
-								err := dfFileOptions.DeleteRowByColumnValueAndSave(
-									dfFileOptions.Columns[0],
-									m.options[m.selected],
-									existFilesPath,
-								)
+								err := m.changeCatalog(func(df *dataFrame.DataFrame) error {
+									_, err := df.DeleteRowsWhereAndSave(
+										catalogColumn,
+										dataFrame.Equals(m.options[m.selected]),
+										existFilesPath,
+									)
+									return err
+								})
 
 								m.options = append(m.options[:m.selected], m.options[m.selected+1:]...)
 
+								if m.selected >= len(m.options) && m.selected > 0 {
+									m.selected--
+								}
+
 								if len(m.options) < 1 {
 									m.running = false
 									m.parent.Start()
@@ -182,9 +240,12 @@ func (m *Menu) draw() {
 	appUtils.DrawVerticalBorders()
 	appUtils.DrawHeader("DeckBuilder v0.1.2")
 
-	if m.parent != nil && m.parent.name == "Select file from catalog" {
-		appUtils.PrintHotkeyBar("  ▲/  ▼- select; D - delete; Enter - select; Esc - exit.", false)
-	} else {
+	switch {
+	case m.parent != nil && m.parent.name == "Select file from catalog":
+		appUtils.PrintHotkeyBar("  ▲/  ▼- select; D - delete; Enter - select; Ctrl+Z/Y - undo/redo catalog; Esc - exit.", false)
+	case m.undoesCatalog():
+		appUtils.PrintHotkeyBar("  ▲/  ▼- select; Enter - select; Ctrl+Z/Y - undo/redo catalog; Esc - exit.", false)
+	default:
 		appUtils.PrintHotkeyBar("  ▲/  ▼- select; Enter - select; Esc - exit.", false)
 	}
 
@@ -203,6 +264,12 @@ func (m *Menu) selectOption() error {
 				"Word",
 				"Word-Translate",
 				"Show",
+				"Stats",
+				"Normalize",
+				"Sort",
+				"Settings",
+				"Import",
+				"Export",
 			}
 
 			menu := newSubMenu(m.options[m.selected], m, options)
@@ -220,7 +287,9 @@ func (m *Menu) selectOption() error {
 
 			if path != "" {
 
-				err := dfFileOptions.AddRowAndSave([]string{path}, existFilesPath)
+				err := m.changeCatalog(func(df *dataFrame.DataFrame) error {
+					return df.AddRowAndSave([]string{path}, existFilesPath)
+				})
 
 				if err != nil {
 					appUtils.PrintHotkeyBar(fmt.Sprintf("Error: %s", err.Error()), true)
@@ -228,10 +297,17 @@ func (m *Menu) selectOption() error {
 					appUtils.PrintHotkeyBar(fmt.Sprintf("%s - successfully added", path), true)
 				}
 			}
+		// "Import Anki package"
+		case 2:
+			return m.importPackage()
 		}
 	case "Select file from catalog":
 
-		options, err := dfFileOptions.GetColumnByName(dfFileOptions.Columns[0])
+		if err := m.catalogLoad.wait(); err != nil {
+			return err
+		}
+
+		options, err := m.catalog.Column(catalogColumn)
 		if err != nil {
 			return err
 		}
@@ -247,6 +323,10 @@ func (m *Menu) selectOption() error {
 		return nil
 	case "Word", "Word-Translate":
 
+		if ok, err := checkDeck(m.options[m.selected]); !ok {
+			return err
+		}
+
 		wordAdder := &fileUtils.WordAdder{}
 		err := wordAdder.Start(m.name, m.options[m.selected])
 
@@ -255,23 +335,182 @@ func (m *Menu) selectOption() error {
 		}
 	case "Show":
 
-		df := dataFrame.NewDataFrame(';')
-		err := df.LoadCSV(m.options[m.selected])
-		if err != nil {
+		if ok, err := checkDeck(m.options[m.selected]); !ok {
 			return err
 		}
 
-		options := df.GetRowsAsStrings(" - ")
+		view, err := newRowView(m.options[m.selected])
+		if err != nil {
+			return err
+		}
 
-		if len(options) > 0 {
-			menu := newSubMenu(m.options[m.selected], m, options)
-			m.menus = append(m.menus, menu)
-			menu.Start()
+		if view.rows.Len() > 0 {
+			return view.Start()
 		} else {
+			view.rows.Close()
 			return errors.New("no word's add new")
 		}
+	case "Stats":
+
+		if ok, err := checkDeck(m.options[m.selected]); !ok {
+			return err
+		}
+
+		return showStats(m.options[m.selected])
+	case "Normalize":
+
+		if ok, err := checkDeck(m.options[m.selected]); !ok {
+			return err
+		}
+
+		return normalizeDeck(m.options[m.selected])
+	case "Sort":
+
+		if ok, err := checkDeck(m.options[m.selected]); !ok {
+			return err
+		}
+
+		return sortDeck(m.options[m.selected])
+	case "Settings":
+		return editSettings(m.options[m.selected])
+	case "Import":
+
+		if ok, err := checkDeck(m.options[m.selected]); !ok {
+			return err
+		}
+
+		return importDeck(m.options[m.selected])
+	case "Export":
+
+		if ok, err := checkDeck(m.options[m.selected]); !ok {
+			return err
+		}
+
+		return exportDeck(m.options[m.selected])
 	default:
 		return nil
 	}
 	return nil
 }
+
+// changeCatalog makes a change of the catalog that saves it. If another DeckBuilder
+// has changed the catalog file in the meantime, both changes are kept
+func (m *Menu) changeCatalog(change func(df *dataFrame.DataFrame) error) error {
+
+	// A catalog that failed to load has no columns to change
+	if err := m.catalogLoad.wait(); err != nil {
+		return err
+	}
+
+	return m.catalog.Write(func(df *dataFrame.DataFrame) error {
+
+		err := change(df)
+		if !errors.Is(err, dataFrame.ErrFileChanged) {
+			return err
+		}
+
+		if err := df.MergeCSV(existFilesPath); err != nil {
+			return err
+		}
+
+		return df.SaveCSV(existFilesPath)
+	})
+}
+
+// openDeck loads the deck at path according to its settings
+func openDeck(path string) (*dataFrame.DataFrame, error) {
+
+	config, err := deckConfig.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	df := config.NewDataFrame()
+
+	if err := df.LoadCSV(path); err != nil {
+		return nil, err
+	}
+
+	journal, err := config.OpenJournal()
+	if err != nil {
+		return nil, err
+	}
+
+	df.SetJournal(journal)
+
+	// Entries are identified by the normalized first column, as in WordAdder
+	if err := df.SetKey(config.Normalization().Comparison(), df.Columns[0]); err != nil {
+		return nil, err
+	}
+
+	return df, nil
+}
+
+// checkDeck validates the deck at path and shows the problems found, if any.
+// Returns false if the deck should not be opened
+func checkDeck(path string) (bool, error) {
+
+	config, err := deckConfig.Open(path)
+	if err != nil {
+		return false, err
+	}
+
+	// The extra columns of the deck, like the note fields of an imported package, may follow Translation
+	optional := slices.Concat(config.Fields(), fileUtils.OptionalDeckColumns)
+
+	report, err := config.NewDataFrame().Validate(path, fileUtils.DeckColumns, optional...)
+	if err != nil {
+		return false, err
+	}
+
+	if !report.OK() {
+
+		// Problems that keep the deck from loading, like broken quoting, cannot be skipped
+		if err := config.NewDataFrame().LoadCSV(path); err != nil {
+			title := fmt.Sprintf("%s: %d problem(s) found, the deck cannot be opened: %s. Esc - back", path, len(report.Issues), err)
+			appUtils.ShowLines(title, report.Lines())
+			return false, nil
+		}
+
+		title := fmt.Sprintf("%s: %d problem(s) found. Enter - open anyway", path, len(report.Issues))
+
+		if !appUtils.ShowLines(title, report.Lines()) {
+			return false, nil
+		}
+	}
+
+	return true, checkEncoding(path, config, report.Encoding)
+}
+
+// checkEncoding offers to convert a deck that is not in UTF-8 to UTF-8.
+// The answer is kept in the deck settings, so it is asked once per deck
+func checkEncoding(path string, config *deckConfig.Config, encoding dataFrame.Encoding) error {
+
+	if encoding == dataFrame.EncodingUTF8 || config.Get(deckConfig.KeyEncoding) != "" {
+		return nil
+	}
+
+	answer, _ := appUtils.GetInput(
+		fmt.Sprintf("%s is in %s. Convert it to UTF-8? (y/N): ", path, encoding),
+		false,
+	)
+
+	value := "keep"
+	if strings.EqualFold(strings.TrimSpace(answer), "y") {
+		value = "utf-8"
+	}
+
+	if err := config.Set(deckConfig.KeyEncoding, value); err != nil {
+		return err
+	}
+
+	if err := config.Save(); err != nil {
+		return err
+	}
+
+	if value == "keep" {
+		return nil
+	}
+
+	return rewriteDeck(path, config)
+}
@@ -1,10 +1,17 @@
 package app
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/Your-RoGr/DeckBuilder/src/dataFrame"
+	"github.com/Your-RoGr/DeckBuilder/src/deckConfig"
+	"github.com/Your-RoGr/DeckBuilder/src/importer"
 	"github.com/Your-RoGr/DeckBuilder/src/testUtils"
 )
 
@@ -23,8 +30,8 @@ func TestNewMainMenu_createsMenuAndCSV(t *testing.T) {
 		t.Errorf("Expected name 'General', got '%s'", menu.name)
 	}
 
-	if len(menu.options) != 2 {
-		t.Errorf("Expected 2 options, got %d", len(menu.options))
+	if len(menu.options) != 3 {
+		t.Errorf("Expected 3 options, got %d", len(menu.options))
 	}
 
 	if _, err := os.Stat(path); err != nil {
@@ -51,6 +58,21 @@ func TestNewSubMenu_works(t *testing.T) {
 	}
 }
 
+func TestMenu_undoesCatalog(t *testing.T) {
+
+	general := &Menu{name: "General"}
+	modes := newSubMenu("Select file from catalog", general, []string{"Word", "Show"})
+	decks := newSubMenu("Show", modes, []string{"deck.csv"})
+
+	if !general.undoesCatalog() || !decks.undoesCatalog() {
+		t.Error("Expected the main menu and the lists of decks to undo the catalog")
+	}
+
+	if modes.undoesCatalog() {
+		t.Error("Expected no undo in the menu of the modes")
+	}
+}
+
 func TestDataFrame_AddRowAndSave_and_LoadCSV_roundtrip(t *testing.T) {
 
 	path := testUtils.TempCSVPath(t)
@@ -154,14 +176,18 @@ func TestMenu_selectOption_General_SelectFileFromCatalog(t *testing.T) {
 
 func TestMenu_selectOption_SelectFileFromCatalog_empty(t *testing.T) {
 
-	dfFileOptions = dataFrame.NewDataFrame(';')
-	dfFileOptions.Columns = []string{"Option"}
-	dfFileOptions.Data = [][]string{}
+	catalog := dataFrame.NewSyncDataFrame(';')
+	_ = catalog.Write(func(df *dataFrame.DataFrame) error {
+		df.Columns = []string{"Option"}
+		df.Data = [][]string{}
+		return nil
+	})
 
 	menu := &Menu{
 		name:     "Select file from catalog",
 		options:  []string{"somefile.csv"},
 		selected: 0,
+		catalog:  catalog,
 	}
 
 	err := menu.selectOption()
@@ -169,3 +195,486 @@ func TestMenu_selectOption_SelectFileFromCatalog_empty(t *testing.T) {
 		t.Errorf("Expected error about missing files, got %v", err)
 	}
 }
+
+func TestMenu_selectOption_SelectFileFromCatalog_loadError(t *testing.T) {
+
+	catalog := dataFrame.NewSyncDataFrame(';')
+	missing := filepath.Join(t.TempDir(), "missing.csv")
+
+	menu := &Menu{
+		name:        "Select file from catalog",
+		options:     []string{"somefile.csv"},
+		catalog:     catalog,
+		catalogLoad: &catalogLoad{done: catalog.LoadCSVAsync(context.Background(), missing)},
+	}
+
+	err := menu.selectOption()
+	if err == nil || !strings.Contains(err.Error(), "the catalog") {
+		t.Errorf("Expected the error of loading the catalog, got %v", err)
+	}
+
+	// The catalog is not written without its columns
+	err = menu.changeCatalog(func(df *dataFrame.DataFrame) error {
+		return df.AddRowAndSave([]string{"deck.csv"}, existFilesPath)
+	})
+	if err == nil {
+		t.Error("Expected changes of a catalog that failed to load to fail")
+	}
+}
+
+func TestCheckDeck_validDeck(t *testing.T) {
+
+	path := testUtils.TempCSVPath(t)
+
+	if err := dataFrame.CreateNewCSV(path, []string{"Word", "Translation"}, ';'); err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := checkDeck(path)
+	if err != nil || !ok {
+		t.Errorf("Expected valid deck to open, got %v, %v", ok, err)
+	}
+}
+
+func TestCheckDeck_optionalColumns(t *testing.T) {
+
+	path := testUtils.TempCSVPath(t)
+
+	if err := dataFrame.CreateNewCSV(path, []string{"Word", "Translation", "Tags", "Deck"}, ';'); err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := checkDeck(path)
+	if err != nil || !ok {
+		t.Errorf("Expected deck with Tags and Deck columns to open, got %v, %v", ok, err)
+	}
+}
+
+func TestCheckEncoding_askedOnce(t *testing.T) {
+
+	path := testUtils.TempCSVPath(t)
+
+	config, err := deckConfig.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checkEncoding(path, config, dataFrame.EncodingUTF8); err != nil {
+		t.Errorf("Expected UTF-8 deck to be accepted, got %v", err)
+	}
+
+	// A deck with an answer saved is not asked again
+	_ = config.Set(deckConfig.KeyEncoding, "keep")
+
+	if err := checkEncoding(path, config, dataFrame.EncodingWindows1251); err != nil {
+		t.Errorf("Expected kept encoding to be accepted, got %v", err)
+	}
+}
+
+func TestCheckDeck_missingDeck(t *testing.T) {
+
+	ok, err := checkDeck(testUtils.TempCSVPath(t))
+	if err == nil || ok {
+		t.Errorf("Expected error for missing deck, got %v, %v", ok, err)
+	}
+}
+
+func TestOpenDeck_detectsDelimiter(t *testing.T) {
+
+	path := testUtils.TempCSVPath(t)
+
+	if err := os.WriteFile(path, []byte("Word,Translation\ncat,кошка\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	df, err := openDeck(path)
+	if err != nil {
+		t.Fatalf("openDeck error: %v", err)
+	}
+
+	if len(df.Columns) != 2 || df.Data[0][1] != "кошка" {
+		t.Errorf("Expected comma-separated deck to be split, got %v %v", df.Columns, df.Data)
+	}
+
+	if keys := df.KeyColumns(); len(keys) != 1 || keys[0] != "Word" {
+		t.Errorf("Expected entries to be keyed by Word, got %v", keys)
+	}
+}
+
+func TestRowView_undoRedoDeckEntries(t *testing.T) {
+
+	path := testUtils.TempCSVPath(t)
+
+	df := dataFrame.NewDataFrame(';')
+	df.Columns = []string{"Word", "Translation"}
+	df.SetJournal(dataFrame.NewJournal())
+	_ = df.AddRowAndSave([]string{"cat", "кошка"}, path)
+
+	view, err := newRowView(path)
+	if err != nil {
+		t.Fatalf("newRowView error: %v", err)
+	}
+	view.deck = df
+
+	if err := view.undo(false); err != nil {
+		t.Fatalf("undo error: %v", err)
+	}
+
+	if view.rows.Len() != 0 || view.selected != 0 || view.position() != "0/0" {
+		t.Errorf("Expected no entries after undo, got %d at %s", view.rows.Len(), view.position())
+	}
+
+	if err := view.undo(true); err != nil {
+		t.Fatalf("redo error: %v", err)
+	}
+
+	if rows, _ := view.rows.Rows(0, 1); len(rows) != 1 || rows[0][1] != "кошка" {
+		t.Errorf("Expected entry back after redo, got %v", rows)
+	}
+
+	view.rows.Close()
+}
+
+func TestRowView_move(t *testing.T) {
+
+	path := testUtils.TempCSVPath(t)
+
+	df := dataFrame.NewDataFrame(';')
+	df.Columns = []string{"Word", "Translation"}
+	for i := 0; i < 100; i++ {
+		df.Data = append(df.Data, []string{fmt.Sprintf("word%d", i), ""})
+	}
+	_ = df.SaveCSV(path)
+
+	view, err := newRowView(path)
+	if err != nil {
+		t.Fatalf("newRowView error: %v", err)
+	}
+	defer view.rows.Close()
+
+	view.move(42)
+	if view.position() != "43/100" {
+		t.Errorf("Expected 43/100, got %s", view.position())
+	}
+
+	view.move(1000)
+	if view.position() != "100/100" {
+		t.Errorf("Expected selection clamped to 100/100, got %s", view.position())
+	}
+
+	view.move(-1000)
+	if view.selected != 0 {
+		t.Errorf("Expected selection clamped to the first row, got %d", view.selected)
+	}
+}
+
+func TestRunCommand_export(t *testing.T) {
+
+	path := testUtils.TempCSVPath(t)
+
+	if err := os.WriteFile(path, []byte("Word;Translation\ncat;кошка\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+
+	ok, err := RunCommand([]string{"export", "-format", "tsv", path}, &out)
+	if !ok || err != nil {
+		t.Fatalf("Expected export to run, got %v, %v", ok, err)
+	}
+
+	if out.String() != "Word\tTranslation\ncat\tкошка\n" {
+		t.Errorf("Unexpected export: %q", out.String())
+	}
+
+	output := path + ".md"
+
+	if _, err := RunCommand([]string{"export", "-format", "markdown", "-o", output, path}, &out); err != nil {
+		t.Fatalf("Export to a file failed: %v", err)
+	}
+
+	if data, _ := os.ReadFile(output); !strings.HasPrefix(string(data), "| Word | Translation |") {
+		t.Errorf("Unexpected export file: %q", data)
+	}
+
+	if _, err := RunCommand([]string{"export", "-format", "xlsx", path}, &out); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestRunCommand_notCommand(t *testing.T) {
+
+	if ok, _ := RunCommand([]string{"-test.v"}, os.Stdout); ok {
+		t.Error("Expected arguments without a command to start the menu")
+	}
+}
+
+func TestImportRows_addsNewEntriesOnce(t *testing.T) {
+
+	path := testUtils.TempCSVPath(t)
+
+	if err := os.WriteFile(path, []byte("Word;Translation\ncat;кошка\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	deck, err := openDeck(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The file is changed after the deck was opened, so the import is retried on it
+	if err := os.WriteFile(path, []byte("Word;Translation\ncat;кошка\nowl;сова\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	added, err := importRows(deck, path, [][]string{{"Cat", "кот"}, {"dog", "собака"}, {"owl", "сова"}, {"dog", "пёс"}})
+	if err != nil {
+		t.Fatalf("importRows error: %v", err)
+	}
+
+	if added != 1 {
+		t.Errorf("Expected 1 entry added, got %d", added)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "Word;Translation\ncat;кошка\nowl;сова\ndog;собака\n" {
+		t.Errorf("Unexpected deck: %q", data)
+	}
+
+	if ok, err := deck.UndoAndSave(path); !ok || err != nil {
+		t.Fatalf("Undo error: %v, %v", ok, err)
+	}
+
+	if len(deck.Data) != 2 {
+		t.Errorf("Expected the import to be undone at once, got %v", deck.Data)
+	}
+}
+
+func TestPackageColumns(t *testing.T) {
+
+	fields := []string{"Front", "Back", "Example", "Audio, slow", "Tags", "Deck"}
+	columns, mapping, extra := packageColumns(fields)
+
+	want := []string{"Word", "Translation", "Example", "Audio  slow", "Tags", "Deck"}
+	if !reflect.DeepEqual(columns, want) {
+		t.Errorf("Unexpected columns: %q", columns)
+	}
+
+	if !reflect.DeepEqual(mapping, importer.Mapping{0, 1, 2, 3, 4, 5}) {
+		t.Errorf("Unexpected mapping: %v", mapping)
+	}
+
+	if !reflect.DeepEqual(extra, []string{"Example", "Audio  slow"}) {
+		t.Errorf("Unexpected extra columns: %q", extra)
+	}
+}
+
+func TestImportedRows(t *testing.T) {
+
+	path := testUtils.TempCSVPath(t)
+
+	config, err := deckConfig.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config.Set(deckConfig.KeyTags, "english")
+	config.Set(deckConfig.KeyDeck, "Words")
+
+	columns := []string{"Word", "Translation", "Tags", "Deck"}
+	rows := importedRows(columns, importer.Mapping{0, 1, 2, -1}, [][]string{
+		{"cat", "кошка", "Animals  pets"},
+		{"", "пусто", ""},
+		{"dog", "собака", ""},
+	}, config)
+
+	expected := [][]string{
+		{"cat", "кошка", "Animals pets", "Words"},
+		{"dog", "собака", "english", "Words"},
+	}
+
+	if fmt.Sprint(rows) != fmt.Sprint(expected) {
+		t.Errorf("Rows mismatch: %v", rows)
+	}
+}
+
+func TestComputeStats(t *testing.T) {
+
+	path := testUtils.TempCSVPath(t)
+
+	data := "Word;Translation;Tags\n" +
+		"cat;кошка;animals pets\n" +
+		"Cat ;;animals\n" +
+		"dog;;\n" +
+		"encyclopaedia;энциклопедия;books animals books\n" +
+		"CAT;кот;\n"
+
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	df := dataFrame.NewDataFrame(';')
+	if err := df.LoadCSV(path); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := computeStats(df, info, dataFrame.CompareNormalized)
+
+	if stats.entries != 5 || stats.missingTranslation != 2 || stats.untagged != 2 {
+		t.Errorf("Unexpected counts: %+v", stats)
+	}
+
+	if fmt.Sprint(stats.duplicates) != "[{cat 3}]" {
+		t.Errorf("Unexpected duplicates: %v", stats.duplicates)
+	}
+
+	if fmt.Sprint(stats.lengths) != "[4 0 0 1 0 0]" || stats.minLength != 3 || stats.maxLength != 13 {
+		t.Errorf("Unexpected lengths: %v, min %d, max %d", stats.lengths, stats.minLength, stats.maxLength)
+	}
+
+	if fmt.Sprint(stats.tags) != "[{animals 3} {books 1} {pets 1}]" {
+		t.Errorf("Unexpected tags: %v", stats.tags)
+	}
+
+	lines := strings.Join(stats.lines(df.Columns), "\n")
+
+	for _, expected := range []string{
+		"Entries: 5",
+		"Without translation: 2",
+		"Duplicates: 1 word(s) entered 2 extra time(s)",
+		"Word length (characters): min 3, average 5.0, max 13",
+		fmt.Sprintf("File size: %d B", len(data)),
+	} {
+		if !strings.Contains(lines, expected) {
+			t.Errorf("Expected %q in:\n%s", expected, lines)
+		}
+	}
+}
+
+func TestApplyNormalization(t *testing.T) {
+
+	path := testUtils.TempCSVPath(t)
+	data := "Word;Translation;Tags\n  Hello   world ;мир;a  b\ncafe\u0301;кафе;\nok;да;x\nlong  word;длинный;;extra\n"
+
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	deck, err := openDeck(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pipeline, _ := dataFrame.ParsePipeline(deckConfig.DefaultNormalization)
+
+	changes := normalizeChanges(deck, pipeline)
+	if len(changes) != 2 || changes[0].index != 0 || changes[1].index != 1 {
+		t.Fatalf("Unexpected changes: %v", changes)
+	}
+
+	preview := strings.Join(normalizePreview(deck, changes, pipeline), "\n")
+	if !strings.Contains(preview, "  Hello   world  - мир - a  b  →  Hello world - мир - a b") {
+		t.Errorf("Unexpected preview:\n%s", preview)
+	}
+
+	// The entry with an extra field is neither truncated nor normalized
+	if !strings.Contains(preview, "1 entry(s) with more fields than the deck has columns are left as they are") {
+		t.Errorf("Expected the entry with extra fields to be reported:\n%s", preview)
+	}
+
+	changed, err := applyNormalization(deck, path, pipeline)
+	if err != nil || changed != 2 {
+		t.Fatalf("applyNormalization = %d, %v", changed, err)
+	}
+
+	saved, _ := os.ReadFile(path)
+	if string(saved) != "Word;Translation;Tags\nHello world;мир;a b\ncaf\u00e9;кафе;\nok;да;x\nlong  word;длинный;;extra\n" {
+		t.Errorf("Unexpected deck: %q", saved)
+	}
+
+	if ok, err := deck.UndoAndSave(path); !ok || err != nil {
+		t.Fatalf("Undo error: %v, %v", ok, err)
+	}
+
+	if deck.Data[0][0] != "  Hello   world " || deck.Data[1][0] != "cafe\u0301" {
+		t.Errorf("Expected the normalization to be undone at once, got %q", deck.Data)
+	}
+}
+
+func TestApplySort(t *testing.T) {
+
+	path := testUtils.TempCSVPath(t)
+	data := "Word;Translation\nяблоко;apple\nthe house;дом\nёлка;fir\nель;spruce\napple;яблоко\n"
+
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	deck, err := openDeck(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	moved, err := applySort(deck, path, deckOrder{column: "Word", ignoreArticles: true})
+	if err != nil || moved != 2 {
+		t.Fatalf("applySort = %d, %v", moved, err)
+	}
+
+	saved, _ := os.ReadFile(path)
+	if string(saved) != "Word;Translation\napple;яблоко\nthe house;дом\nёлка;fir\nель;spruce\nяблоко;apple\n" {
+		t.Errorf("Unexpected deck: %q", saved)
+	}
+
+	if moved, err := applySort(deck, path, deckOrder{column: "Word", ignoreArticles: true}); moved != 0 || err != nil {
+		t.Errorf("Expected a sorted deck to stay, got %d, %v", moved, err)
+	}
+
+	if ok, err := deck.UndoAndSave(path); !ok || err != nil {
+		t.Fatalf("Undo error: %v, %v", ok, err)
+	}
+
+	if saved, _ := os.ReadFile(path); string(saved) != data {
+		t.Errorf("Expected the sort to be undone at once, got %q", saved)
+	}
+}
+
+func TestRowView_sorted(t *testing.T) {
+
+	path := testUtils.TempCSVPath(t)
+	data := "Word;Translation\nbanana;банан\napple;яблоко\ncherry;вишня\n"
+
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	view, err := newRowView(path)
+	if err != nil {
+		t.Fatalf("newRowView error: %v", err)
+	}
+	defer view.rows.Close()
+
+	view.order = &deckOrder{column: "Word", descending: true}
+	if err := view.reload(); err != nil {
+		t.Fatalf("reload error: %v", err)
+	}
+
+	view.visibleRows = 2
+	rows, err := view.visible()
+	if err != nil || len(rows) != 2 || rows[0][0] != "cherry" || rows[1][0] != "banana" {
+		t.Errorf("Unexpected visible rows: %v, %v", rows, err)
+	}
+
+	view.move(2)
+	if view.index() != 1 {
+		t.Errorf("Expected the last entry shown to be apple at 1, got %d", view.index())
+	}
+
+	if saved, _ := os.ReadFile(path); string(saved) != data {
+		t.Errorf("Expected the deck file to stay unchanged, got %q", saved)
+	}
+}
@@ -0,0 +1,228 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Your-RoGr/DeckBuilder/src/appUtils"
+	"github.com/Your-RoGr/DeckBuilder/src/dataFrame"
+	"github.com/Your-RoGr/DeckBuilder/src/deckConfig"
+	"github.com/Your-RoGr/DeckBuilder/src/fileUtils"
+)
+
+// normalizeChange is an entry whose values change when the deck is normalized
+type normalizeChange struct {
+	index int
+	row   []string
+}
+
+// normalizeDeck shows how the normalization set for the deck at path changes its entries
+// and applies it, so that entries added before the setting was changed follow it too
+func normalizeDeck(path string) error {
+
+	config, err := deckConfig.Open(path)
+	if err != nil {
+		return err
+	}
+
+	lock, err := fileUtils.LockDeck(config)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	df := config.NewDataFrame()
+	if err := df.LoadCSV(path); err != nil {
+		return err
+	}
+
+	journal, err := config.OpenJournal()
+	if err != nil {
+		return err
+	}
+
+	df.SetJournal(journal)
+
+	pipeline := config.Normalization()
+	changes := normalizeChanges(df, pipeline)
+
+	if len(changes) == 0 {
+
+		message := fmt.Sprintf("%s - the entries are already normalized (%s)", path, pipeline)
+		if long := extraFieldRows(df); long > 0 {
+			message += fmt.Sprintf(", %d entry(s) with extra fields left as they are", long)
+		}
+
+		appUtils.PrintHotkeyBar(message, true)
+		return nil
+	}
+
+	title := fmt.Sprintf("Normalize %d entry(s) of %s with %s? Enter - normalize", len(changes), path, pipeline)
+	if !appUtils.ShowLines(title, normalizePreview(df, changes, pipeline)) {
+		return nil
+	}
+
+	changed, err := applyNormalization(df, path, pipeline)
+	if err != nil {
+		return err
+	}
+
+	appUtils.PrintHotkeyBar(fmt.Sprintf("%s - %d entry(s) normalized", path, changed), true)
+
+	return nil
+}
+
+// normalizeRow returns the values of row normalized by pipeline. The Tags column only
+// gets its tags separated by single spaces, and the Deck column is kept as it is
+func normalizeRow(columns, row []string, pipeline dataFrame.Pipeline) []string {
+
+	values := slices.Clone(row)
+
+	for i := range values {
+
+		column := ""
+		if i < len(columns) {
+			column = columns[i]
+		}
+
+		switch column {
+		case deckConfig.ColumnTags:
+			values[i] = deckConfig.NormalizeTags(values[i])
+		case deckConfig.ColumnDeck:
+		default:
+			values[i] = pipeline.Apply(values[i])
+		}
+	}
+
+	return values
+}
+
+// normalizeChanges returns the entries of df that pipeline changes, with their new values.
+// Entries with more fields than the deck has columns are left as they are
+func normalizeChanges(df *dataFrame.DataFrame, pipeline dataFrame.Pipeline) []normalizeChange {
+
+	var changes []normalizeChange
+
+	for i, row := range df.Data {
+
+		if len(row) > len(df.Columns) {
+			continue
+		}
+
+		// Rows with missing values get empty ones, as UpdateRow needs a value per column
+		values := make([]string, len(df.Columns))
+		copy(values, row)
+
+		if normalized := normalizeRow(df.Columns, values, pipeline); !slices.Equal(normalized, row) {
+			changes = append(changes, normalizeChange{index: i, row: normalized})
+		}
+	}
+
+	return changes
+}
+
+// normalizePreview describes the first changes as "before → after" lines and counts
+// the entries that will share their word with another entry
+func normalizePreview(df *dataFrame.DataFrame, changes []normalizeChange, pipeline dataFrame.Pipeline) []string {
+
+	lines := []string{fmt.Sprintf("Normalization: %s", pipeline), "", "Changes:"}
+
+	for _, change := range changes[:min(len(changes), previewRows)] {
+		lines = append(lines, fmt.Sprintf("  %s  →  %s",
+			strings.Join(df.Data[change.index], " - "), strings.Join(change.row, " - ")))
+	}
+
+	if len(changes) > previewRows {
+		lines = append(lines, fmt.Sprintf("  ... and %d more", len(changes)-previewRows))
+	}
+
+	compare := pipeline.Comparison()
+	seen := make(map[string]bool)
+	duplicates := 0
+
+	for _, row := range df.Data {
+
+		key := compare(cellAt(row, 0))
+		if key != "" && seen[key] {
+			duplicates++
+		}
+
+		seen[key] = true
+	}
+
+	if duplicates > 0 {
+		lines = append(lines, "", fmt.Sprintf(
+			"%d entry(s) will repeat the %s of an earlier entry; see Stats and remove them in Show",
+			duplicates, strings.ToLower(df.Columns[0]),
+		))
+	}
+
+	if long := extraFieldRows(df); long > 0 {
+		lines = append(lines, "", fmt.Sprintf(
+			"%d entry(s) with more fields than the deck has columns are left as they are", long,
+		))
+	}
+
+	return lines
+}
+
+// extraFieldRows returns the number of entries of df with more fields than columns
+func extraFieldRows(df *dataFrame.DataFrame) int {
+
+	count := 0
+
+	for _, row := range df.Data {
+		if len(row) > len(df.Columns) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// applyNormalization normalizes the entries of df and saves the deck once, so that the
+// whole normalization is undone at once. If the deck file was changed in the meantime,
+// it is loaded again and normalized. Returns the number of entries changed
+func applyNormalization(df *dataFrame.DataFrame, path string, pipeline dataFrame.Pipeline) (int, error) {
+
+	for retried := false; ; retried = true {
+
+		changes := normalizeChanges(df, pipeline)
+		if len(changes) == 0 {
+			return 0, nil
+		}
+
+		if err := df.Begin(); err != nil {
+			return 0, err
+		}
+
+		for _, change := range changes {
+			if err := df.UpdateRow(change.index, change.row); err != nil {
+				return 0, errors.Join(err, df.Rollback())
+			}
+		}
+
+		err := df.Commit(path)
+
+		if errors.Is(err, dataFrame.ErrFileChanged) && !retried {
+
+			if err := df.Rollback(); err != nil {
+				return 0, err
+			}
+
+			if err := df.LoadCSV(path); err != nil {
+				return 0, err
+			}
+
+			continue
+		}
+
+		if err != nil {
+			return 0, errors.Join(err, df.Rollback())
+		}
+
+		return len(changes), nil
+	}
+}
@@ -0,0 +1,270 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Your-RoGr/DeckBuilder/src/appUtils"
+	"github.com/Your-RoGr/DeckBuilder/src/dataFrame"
+	"github.com/Your-RoGr/DeckBuilder/src/deckConfig"
+	"github.com/Your-RoGr/DeckBuilder/src/fileUtils"
+	"github.com/nsf/termbox-go"
+)
+
+// rowView shows the entries of a deck, reading from disk only the rows visible on the screen.
+// The whole deck is loaded into memory only when an entry is edited, deleted or undone,
+// or read when the view is sorted
+type rowView struct {
+	path         string
+	rows         *dataFrame.RowFile
+	deck         *dataFrame.DataFrame // loaded on the first change, nil until then
+	lock         *dataFrame.FileLock  // lock of the deck, taken together with loading it
+	normalize    dataFrame.Pipeline   // normalization of edited values, set with loading the deck
+	order        *deckOrder           // order of the entries shown, the order of the file if nil
+	positions    []int                // positions in the file of the entries shown, nil if not sorted
+	selected     int
+	scrollOffset int
+	visibleRows  int
+	status       string // message shown instead of the path until the next key
+}
+
+// newRowView indexes the deck at path for display
+func newRowView(path string) (*rowView, error) {
+
+	v := &rowView{path: path, visibleRows: 1}
+
+	return v, v.reload()
+}
+
+// reload indexes the deck file again after it has been changed
+func (v *rowView) reload() error {
+
+	config, err := deckConfig.Open(v.path)
+	if err != nil {
+		return err
+	}
+
+	rows, err := dataFrame.OpenRowFile(v.path, config.Delimiter())
+	if err != nil {
+		return err
+	}
+
+	if v.rows != nil {
+		v.rows.Close()
+	}
+
+	v.rows = rows
+
+	// The order is computed again, as a change may have moved the entries
+	if v.positions, err = v.sortIndexes(); err != nil {
+		return err
+	}
+
+	v.move(0)
+
+	return nil
+}
+
+// loadDeck returns the deck loaded into memory, loading it and taking its lock on the first call.
+// The deck can be viewed but not changed while another DeckBuilder holds the lock
+func (v *rowView) loadDeck() (*dataFrame.DataFrame, error) {
+
+	if v.deck == nil {
+
+		config, err := deckConfig.Open(v.path)
+		if err != nil {
+			return nil, err
+		}
+
+		lock, err := fileUtils.LockDeck(config)
+		if err != nil {
+			return nil, err
+		}
+
+		deck, err := openDeck(v.path)
+		if err != nil {
+			lock.Unlock()
+			return nil, err
+		}
+
+		v.deck = deck
+		v.lock = lock
+		v.normalize = config.Normalization()
+	}
+
+	return v.deck, nil
+}
+
+// resolveConflict lets the user resolve a failed save of a deck changed outside of
+// DeckBuilder (see fileUtils.ResolveConflict). Discarded changes are removed from the view
+func (v *rowView) resolveConflict(err error) error {
+
+	err = fileUtils.ResolveConflict(v.deck, v.path, err)
+	if errors.Is(err, fileUtils.ErrAborted) {
+		return errors.Join(v.reload(), err)
+	}
+
+	return err
+}
+
+// close closes the deck file and releases the lock of the deck
+func (v *rowView) close() {
+
+	v.rows.Close()
+
+	if v.lock != nil {
+		v.lock.Unlock()
+	}
+}
+
+// index returns the position in the deck file of the selected entry
+func (v *rowView) index() int {
+
+	if v.positions != nil && v.selected < len(v.positions) {
+		return v.positions[v.selected]
+	}
+
+	return v.selected
+}
+
+// move moves the selection by delta rows, keeping it within the deck
+func (v *rowView) move(delta int) {
+	v.selected = max(min(v.selected+delta, v.rows.Len()-1), 0)
+}
+
+// Start runs the view until Esc is pressed. It runs inside the terminal session of the menu
+func (v *rowView) Start() error {
+
+	// reload replaces the RowFile, so the current one is closed
+	defer v.close()
+
+	for {
+		if err := v.draw(); err != nil {
+			return err
+		}
+
+		switch ev := termbox.PollEvent(); ev.Type {
+		case termbox.EventKey:
+
+			var err error
+			v.status = ""
+
+			switch ev.Key {
+			case termbox.KeyArrowUp:
+				v.move(-1)
+			case termbox.KeyArrowDown:
+				v.move(1)
+			case termbox.KeyPgup:
+				v.move(-v.visibleRows)
+			case termbox.KeyPgdn:
+				v.move(v.visibleRows)
+			case termbox.KeyHome:
+				v.move(-v.selected)
+			case termbox.KeyEnd:
+				v.move(v.rows.Len())
+			case termbox.KeyEnter:
+				err = v.editEntry()
+			case termbox.KeyCtrlZ, termbox.KeyCtrlY:
+				err = v.undo(ev.Key == termbox.KeyCtrlY)
+			case termbox.KeyEsc:
+				return nil
+			default:
+				switch ev.Ch {
+				case 'd', 'D':
+					err = v.deleteEntry()
+				case 's', 'S':
+					err = v.sortView()
+				}
+			}
+
+			if err != nil {
+				appUtils.GetInput(err.Error(), false)
+			}
+		case termbox.EventInterrupt, termbox.EventError:
+			return nil
+		}
+	}
+}
+
+// draw reads the visible rows from the deck file and draws them with the position of the selection
+func (v *rowView) draw() error {
+
+	termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
+	_, height := termbox.Size()
+	v.visibleRows = max(height-3, 1)
+
+	if v.selected < v.scrollOffset {
+		v.scrollOffset = v.selected
+	}
+	if v.selected >= v.scrollOffset+v.visibleRows {
+		v.scrollOffset = v.selected - v.visibleRows + 1
+	}
+
+	rows, err := v.visible()
+	if err != nil {
+		return err
+	}
+
+	for i, row := range rows {
+
+		fg := termbox.ColorWhite
+		bg := termbox.ColorDefault
+
+		if v.scrollOffset+i == v.selected {
+			fg = termbox.ColorBlack
+			bg = termbox.ColorCyan
+		}
+
+		appUtils.SetLine(2, i+2, strings.Join(row, " - "), fg, bg)
+	}
+
+	status := v.status
+	if status == "" {
+		status = v.path
+	}
+
+	appUtils.DrawVerticalBorders()
+	appUtils.DrawHeader("DeckBuilder v0.1.2")
+	if v.order != nil {
+		status = fmt.Sprintf("by %s  %s", v.order, status)
+	}
+
+	appUtils.PrintHotkeyBar(fmt.Sprintf("%s  %s", v.position(), status), true)
+	appUtils.PrintHotkeyBar("  ▲/  ▼, PgUp/PgDn - select; D - delete; Enter - edit; S - sort; Ctrl+Z/Y - undo/redo; Esc - exit.", false)
+	termbox.Flush()
+
+	return nil
+}
+
+// visible reads the rows shown on the screen, in the order of the view
+func (v *rowView) visible() ([][]string, error) {
+
+	if v.positions == nil {
+		return v.rows.Rows(v.scrollOffset, v.visibleRows)
+	}
+
+	var rows [][]string
+
+	for _, position := range v.positions[v.scrollOffset:min(len(v.positions), v.scrollOffset+v.visibleRows)] {
+
+		row, err := v.rows.Rows(position, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		rows = append(rows, row...)
+	}
+
+	return rows, nil
+}
+
+// position returns the position of the selection as "selected/total"
+func (v *rowView) position() string {
+
+	if v.rows.Len() == 0 {
+		return "0/0"
+	}
+
+	return fmt.Sprintf("%d/%d", v.selected+1, v.rows.Len())
+}
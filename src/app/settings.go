@@ -0,0 +1,95 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/Your-RoGr/DeckBuilder/src/appUtils"
+	"github.com/Your-RoGr/DeckBuilder/src/deckConfig"
+	"github.com/Your-RoGr/DeckBuilder/src/fileUtils"
+)
+
+// editSettings asks for a new value of every deck setting and saves them.
+// An empty answer keeps the current value, "-" resets it to the default
+func editSettings(path string) error {
+
+	config, err := deckConfig.Open(path)
+	if err != nil {
+		return err
+	}
+
+	header := config.Get(deckConfig.KeyHeader)
+	encoding := config.Get(deckConfig.KeyEncoding)
+	normalization := config.Normalization().String()
+
+	for _, def := range deckConfig.Definitions {
+
+		current := config.Get(def.Key)
+		if current == "" {
+			current = "default"
+		}
+
+		for {
+			value, ok := appUtils.GetInput(
+				fmt.Sprintf("%s, now %s. Enter - keep; '-' - default: ", def.Description, current),
+				false,
+			)
+
+			if !ok {
+				return nil
+			}
+
+			if value == "" {
+				break
+			}
+
+			if value == "-" {
+				value = ""
+			}
+
+			if err := config.Set(def.Key, value); err != nil {
+				appUtils.GetInput("Error: "+err.Error(), false)
+				continue
+			}
+
+			break
+		}
+	}
+
+	if err := config.Save(); err != nil {
+		return err
+	}
+
+	// Rewrite the deck so that the new header format and encoding are used right away
+	if config.Get(deckConfig.KeyHeader) != header || config.Get(deckConfig.KeyEncoding) != encoding {
+		if err := rewriteDeck(path, config); err != nil {
+			return err
+		}
+	}
+
+	appUtils.PrintHotkeyBar(fmt.Sprintf("%s - settings saved", path), true)
+
+	// Entries added before follow the new normalization only once the deck is normalized
+	if config.Normalization().String() != normalization {
+		return normalizeDeck(path)
+	}
+
+	return nil
+}
+
+// rewriteDeck loads the deck at path and saves it again according to its settings
+func rewriteDeck(path string, config *deckConfig.Config) error {
+
+	lock, err := fileUtils.LockDeck(config)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	df := config.NewDataFrame()
+
+	if err := df.LoadCSV(path); err != nil {
+		return err
+	}
+
+	return df.SaveCSV(path)
+}
@@ -0,0 +1,266 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Your-RoGr/DeckBuilder/src/appUtils"
+	"github.com/Your-RoGr/DeckBuilder/src/dataFrame"
+	"github.com/Your-RoGr/DeckBuilder/src/deckConfig"
+	"github.com/Your-RoGr/DeckBuilder/src/fileUtils"
+)
+
+// deckOrder is an order of the entries of a deck chosen by the user
+type deckOrder struct {
+	column         string
+	descending     bool
+	ignoreArticles bool
+}
+
+// key returns the sort key of the order, using the locale-aware collation
+func (o deckOrder) key() dataFrame.SortKey {
+
+	collation := dataFrame.Collation{IgnoreArticles: o.ignoreArticles}
+
+	return dataFrame.SortKey{Column: o.column, Descending: o.descending, Less: collation.Less}
+}
+
+// String describes the order, e.g. "Word ▼, ignoring articles"
+func (o deckOrder) String() string {
+
+	s := o.column + " ▲"
+	if o.descending {
+		s = o.column + " ▼"
+	}
+
+	if o.ignoreArticles {
+		s += ", ignoring articles"
+	}
+
+	return s
+}
+
+// askOrder asks for the column to sort by, the direction and whether leading articles
+// are ignored. With allowNone, "-" chooses the order of the file and nil is returned.
+// Returns false if the user cancelled
+func askOrder(columns []string, allowNone bool) (*deckOrder, bool) {
+
+	prompt := fmt.Sprintf("Sort by (%s): ", strings.Join(columns, ", "))
+	if allowNone {
+		prompt = fmt.Sprintf("Sort by (%s; '-' - file order): ", strings.Join(columns, ", "))
+	}
+
+	var order deckOrder
+
+	for {
+		column, ok := appUtils.GetInputWithValue(prompt, columns[0], true)
+		if !ok {
+			return nil, false
+		}
+
+		column = strings.TrimSpace(column)
+
+		if allowNone && column == "-" {
+			return nil, true
+		}
+
+		if i := slices.IndexFunc(columns, func(c string) bool { return strings.EqualFold(c, column) }); i != -1 {
+			order.column = columns[i]
+			break
+		}
+
+		appUtils.GetInput(fmt.Sprintf("Error: no column %s", column), false)
+	}
+
+	direction, ok := appUtils.GetInputWithValue("Order: (a)scending, (d)escending: ", "a", true)
+	if !ok {
+		return nil, false
+	}
+
+	articles, ok := appUtils.GetInput("Ignore leading articles (the, der, le, ...)? (y)", false)
+	if !ok {
+		return nil, false
+	}
+
+	order.descending = strings.HasPrefix(strings.ToLower(strings.TrimSpace(direction)), "d")
+	order.ignoreArticles = articles == "y"
+
+	return &order, true
+}
+
+// sortDeck asks for an order, shows the first entries of the deck at path in it
+// and saves the deck sorted
+func sortDeck(path string) error {
+
+	config, err := deckConfig.Open(path)
+	if err != nil {
+		return err
+	}
+
+	lock, err := fileUtils.LockDeck(config)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	df := config.NewDataFrame()
+	if err := df.LoadCSV(path); err != nil {
+		return err
+	}
+
+	journal, err := config.OpenJournal()
+	if err != nil {
+		return err
+	}
+
+	df.SetJournal(journal)
+
+	order, ok := askOrder(df.Columns, false)
+	if !ok {
+		return nil
+	}
+
+	sorted, err := df.Sort(order.key())
+	if err != nil {
+		return err
+	}
+
+	title := fmt.Sprintf("Save %s sorted by %s? Enter - save", path, order)
+	if !appUtils.ShowLines(title, sortPreview(sorted)) {
+		return nil
+	}
+
+	moved, err := applySort(df, path, *order)
+	if err != nil {
+		return err
+	}
+
+	if moved == 0 {
+		appUtils.PrintHotkeyBar(fmt.Sprintf("%s - the entries are already in order", path), true)
+		return nil
+	}
+
+	appUtils.PrintHotkeyBar(fmt.Sprintf("%s - sorted, %d entry(s) moved", path, moved), true)
+
+	return nil
+}
+
+// sortPreview lists the first entries of sorted
+func sortPreview(sorted *dataFrame.DataFrame) []string {
+
+	var lines []string
+
+	for _, row := range sorted.Data[:min(len(sorted.Data), previewRows)] {
+		lines = append(lines, strings.Join(row, " - "))
+	}
+
+	if len(sorted.Data) > previewRows {
+		lines = append(lines, fmt.Sprintf("... and %d more", len(sorted.Data)-previewRows))
+	}
+
+	return lines
+}
+
+// applySort sorts the entries of df and saves the deck, so that the sort is undone at once.
+// If the deck file was changed in the meantime, it is loaded again and sorted.
+// Returns the number of entries moved
+func applySort(df *dataFrame.DataFrame, path string, order deckOrder) (int, error) {
+
+	for retried := false; ; retried = true {
+
+		if err := df.Begin(); err != nil {
+			return 0, err
+		}
+
+		moved, err := df.SortRows(order.key())
+		if err != nil || moved == 0 {
+			return 0, errors.Join(err, df.Rollback())
+		}
+
+		err = df.Commit(path)
+
+		if errors.Is(err, dataFrame.ErrFileChanged) && !retried {
+
+			if err := df.Rollback(); err != nil {
+				return 0, err
+			}
+
+			if err := df.LoadCSV(path); err != nil {
+				return 0, err
+			}
+
+			continue
+		}
+
+		if err != nil {
+			return 0, errors.Join(err, df.Rollback())
+		}
+
+		return moved, nil
+	}
+}
+
+// sortView asks for an order of the entries shown, without changing the deck file
+func (v *rowView) sortView() error {
+
+	columns, err := v.columns()
+	if err != nil {
+		return err
+	}
+
+	order, ok := askOrder(columns, true)
+	if !ok {
+		return nil
+	}
+
+	v.order = order
+	v.selected = 0
+
+	return v.reload()
+}
+
+// columns returns the column names of the deck shown
+func (v *rowView) columns() ([]string, error) {
+
+	if v.deck != nil {
+		return v.deck.Columns, nil
+	}
+
+	df, err := v.readDeck()
+	if err != nil {
+		return nil, err
+	}
+
+	return df.Columns, nil
+}
+
+// readDeck reads the deck file into memory without taking its lock, for sorting the view
+func (v *rowView) readDeck() (*dataFrame.DataFrame, error) {
+
+	config, err := deckConfig.Open(v.path)
+	if err != nil {
+		return nil, err
+	}
+
+	df := config.NewDataFrame()
+
+	return df, df.LoadCSV(v.path)
+}
+
+// sortIndexes returns the positions in the deck file of the entries in the order of
+// the view, or nil if the view shows the entries in the order of the file
+func (v *rowView) sortIndexes() ([]int, error) {
+
+	if v.order == nil {
+		return nil, nil
+	}
+
+	df, err := v.readDeck()
+	if err != nil {
+		return nil, err
+	}
+
+	return df.SortIndexes(v.order.key())
+}
@@ -0,0 +1,258 @@
+package app
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Your-RoGr/DeckBuilder/src/appUtils"
+	"github.com/Your-RoGr/DeckBuilder/src/dataFrame"
+	"github.com/Your-RoGr/DeckBuilder/src/deckConfig"
+)
+
+// lengthBuckets are the upper bounds of the word lengths counted together;
+// longer words are counted in a last bucket
+var lengthBuckets = []int{3, 6, 10, 15, 20}
+
+// Sizes of the lists shown in the statistics
+const (
+	statsDuplicates = 10 // duplicate words listed
+	statsBarWidth   = 30 // width of the longest bar of the length distribution
+)
+
+// deckStats holds the statistics of a deck
+type deckStats struct {
+	entries            int
+	missingTranslation int
+	duplicates         []duplicateGroup // words entered more than once, most repeated first
+	words              int              // entries with a word
+	lengths            []int            // number of words per bucket of lengthBuckets
+	minLength          int
+	maxLength          int
+	totalLength        int
+	hasTags            bool
+	tags               []tagCount // most used first
+	untagged           int
+	size               int64
+	modified           string
+}
+
+// duplicateGroup is a word and how many entries have it after normalization
+type duplicateGroup struct {
+	word  string
+	count int
+}
+
+// tagCount is a tag and the number of entries that have it
+type tagCount struct {
+	tag   string
+	count int
+}
+
+// showStats shows the statistics of the deck at path
+func showStats(path string) error {
+
+	config, err := deckConfig.Open(path)
+	if err != nil {
+		return err
+	}
+
+	df := config.NewDataFrame()
+	if err := df.LoadCSV(path); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	appUtils.ShowLines(fmt.Sprintf("Statistics of %s", path), computeStats(df, info, config.Normalization().Comparison()).lines(df.Columns))
+
+	return nil
+}
+
+// computeStats computes the statistics of the deck in df, whose file is described by info.
+// Words are taken from the first column and translations from the second, and words
+// are duplicates when their forms given by compare are equal
+func computeStats(df *dataFrame.DataFrame, info os.FileInfo, compare dataFrame.Comparison) deckStats {
+
+	stats := deckStats{
+		entries:  len(df.Data),
+		lengths:  make([]int, len(lengthBuckets)+1),
+		size:     info.Size(),
+		modified: info.ModTime().Format("2006-01-02 15:04:05"),
+	}
+
+	tagsIdx := slices.Index(df.Columns, deckConfig.ColumnTags)
+	stats.hasTags = tagsIdx != -1
+
+	groups := make(map[string]*duplicateGroup)
+	var order []string
+	tags := make(map[string]int)
+
+	for _, row := range df.Data {
+
+		word := strings.TrimSpace(cellAt(row, 0))
+
+		if len(df.Columns) > 1 && strings.TrimSpace(cellAt(row, 1)) == "" {
+			stats.missingTranslation++
+		}
+
+		if stats.hasTags {
+
+			fields := strings.Fields(cellAt(row, tagsIdx))
+			if len(fields) == 0 {
+				stats.untagged++
+			}
+
+			for _, tag := range slices.Compact(slices.Sorted(slices.Values(fields))) {
+				tags[tag]++
+			}
+		}
+
+		if word == "" {
+			continue
+		}
+
+		key := compare(word)
+		if group, ok := groups[key]; ok {
+			group.count++
+		} else {
+			groups[key] = &duplicateGroup{word: word, count: 1}
+			order = append(order, key)
+		}
+
+		length := utf8.RuneCountInString(word)
+
+		if stats.words == 0 || length < stats.minLength {
+			stats.minLength = length
+		}
+
+		stats.words++
+		stats.totalLength += length
+		stats.maxLength = max(stats.maxLength, length)
+
+		bucket, _ := slices.BinarySearch(lengthBuckets, length)
+		stats.lengths[bucket]++
+	}
+
+	for _, key := range order {
+		if groups[key].count > 1 {
+			stats.duplicates = append(stats.duplicates, *groups[key])
+		}
+	}
+
+	slices.SortStableFunc(stats.duplicates, func(a, b duplicateGroup) int {
+		return cmp.Compare(b.count, a.count)
+	})
+
+	for tag, count := range tags {
+		stats.tags = append(stats.tags, tagCount{tag: tag, count: count})
+	}
+
+	slices.SortFunc(stats.tags, func(a, b tagCount) int {
+		return cmp.Or(cmp.Compare(b.count, a.count), cmp.Compare(a.tag, b.tag))
+	})
+
+	return stats
+}
+
+// lines renders the statistics for ShowLines
+func (s deckStats) lines(columns []string) []string {
+
+	word, translation := "Word", "Translation"
+	if len(columns) > 0 {
+		word = columns[0]
+	}
+	if len(columns) > 1 {
+		translation = columns[1]
+	}
+
+	lines := []string{
+		fmt.Sprintf("Entries: %d", s.entries),
+		fmt.Sprintf("Without %s: %d", strings.ToLower(translation), s.missingTranslation),
+	}
+
+	extra := 0
+	for _, group := range s.duplicates {
+		extra += group.count - 1
+	}
+
+	lines = append(lines, fmt.Sprintf("Duplicates: %d word(s) entered %d extra time(s)", len(s.duplicates), extra))
+
+	for _, group := range s.duplicates[:min(len(s.duplicates), statsDuplicates)] {
+		lines = append(lines, fmt.Sprintf("  %s ×%d", group.word, group.count))
+	}
+
+	if len(s.duplicates) > statsDuplicates {
+		lines = append(lines, fmt.Sprintf("  ... and %d more", len(s.duplicates)-statsDuplicates))
+	}
+
+	lines = append(lines, "", fmt.Sprintf("%s length (characters):", word))
+
+	if s.words > 0 {
+
+		lines[len(lines)-1] += fmt.Sprintf(" min %d, average %.1f, max %d",
+			s.minLength, float64(s.totalLength)/float64(s.words), s.maxLength)
+
+		most := slices.Max(s.lengths)
+
+		for i, count := range s.lengths {
+
+			label := fmt.Sprintf("%d+", lengthBuckets[len(lengthBuckets)-1]+1)
+			if i < len(lengthBuckets) {
+				low := 1
+				if i > 0 {
+					low = lengthBuckets[i-1] + 1
+				}
+				label = fmt.Sprintf("%d-%d", low, lengthBuckets[i])
+			}
+
+			bar := strings.Repeat("█", (count*statsBarWidth+most-1)/most)
+			lines = append(lines, fmt.Sprintf("  %6s %6d %s", label, count, bar))
+		}
+	}
+
+	if s.hasTags {
+
+		lines = append(lines, "", "Tags:")
+
+		for _, t := range s.tags {
+			lines = append(lines, fmt.Sprintf("  %-20s %6d", t.tag, t.count))
+		}
+
+		lines = append(lines, fmt.Sprintf("  %-20s %6d", "(untagged)", s.untagged))
+	}
+
+	return append(lines, "",
+		fmt.Sprintf("File size: %s", formatSize(s.size)),
+		fmt.Sprintf("Last modified: %s", s.modified),
+	)
+}
+
+// formatSize formats a file size in bytes, KB or MB
+func formatSize(size int64) string {
+
+	switch {
+	case size < 1024:
+		return fmt.Sprintf("%d B", size)
+	case size < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(size)/1024)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+	}
+}
+
+// cellAt returns the value at column idx of row, or "" if the row is shorter
+func cellAt(row []string, idx int) string {
+
+	if idx < len(row) {
+		return row[idx]
+	}
+
+	return ""
+}
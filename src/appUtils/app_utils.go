@@ -1,6 +1,7 @@
 package appUtils
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/mattn/go-runewidth"
@@ -77,26 +78,49 @@ func PrintHotkeyBar(msg string, isUp bool) {
 
 // GetInput - Tooltip for entering the input
 func GetInput(prompt string, inputRequire bool) (string, bool) {
+	return GetInputWithValue(prompt, "", inputRequire)
+}
+
+// GetInputWithValue - Tooltip for entering the input, pre-filled with value
+func GetInputWithValue(prompt string, value string, inputRequire bool) (string, bool) {
+
+	input, _, ok := GetInputWithHotkeys(prompt, value, inputRequire)
+
+	return input, ok
+}
+
+// GetInputWithHotkeys - Tooltip for entering the input, pre-filled with value,
+// that also returns when one of hotkeys is pressed. The pressed hotkey is returned
+// together with the input, or 0 if the input was sent with Enter
+func GetInputWithHotkeys(prompt string, value string, inputRequire bool, hotkeys ...termbox.Key) (string, termbox.Key, bool) {
 
 	termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
 	PrintHotkeyBar(prompt, true)
 	DrawVerticalBorders()
 	DrawHeader("DeckBuilder v0.1.2")
 	PrintHotkeyBar("Enter - send; Esc - exit.", false)
+	input := []rune(value)
+	cursorPos := len(input)
+
+	if len(input) > 0 {
+		drawInput(input, cursorPos)
+	}
+
 	termbox.Flush()
-	var input []rune
-	cursorPos := 0
 
 	for {
 		switch ev := termbox.PollEvent(); ev.Type {
 		case termbox.EventKey:
 			if ev.Key == termbox.KeyEnter {
 				if len(input) > 0 || !inputRequire {
-					return strings.TrimSpace(string(input)), true
+					return strings.TrimSpace(string(input)), 0, true
 				}
 			}
 			if ev.Key == termbox.KeyEsc {
-				return "", false
+				return "", 0, false
+			}
+			if ev.Ch == 0 && slices.Contains(hotkeys, ev.Key) {
+				return strings.TrimSpace(string(input)), ev.Key, true
 			}
 			if ev.Key == termbox.KeyBackspace || ev.Key == termbox.KeyBackspace2 {
 				if cursorPos > 0 {
@@ -120,24 +144,30 @@ func GetInput(prompt string, inputRequire bool) (string, bool) {
 			}
 		}
 
-		width, _ := termbox.Size()
+		drawInput(input, cursorPos)
+		termbox.Flush()
+	}
+}
 
-		for x := 0; x < width; x++ {
-			termbox.SetCell(x+2, 2, ' ', termbox.ColorDefault, termbox.ColorDefault)
-		}
+// drawInput draws the entered text and the cursor
+func drawInput(input []rune, cursorPos int) {
 
-		SetLine(2, 2, string(input), termbox.ColorYellow, termbox.ColorDefault)
+	width, _ := termbox.Size()
 
-		cursorX := 2
-		for i := 0; i < cursorPos; i++ {
-			cursorX += runewidth.RuneWidth(input[i])
-		}
+	for x := 0; x < width; x++ {
+		termbox.SetCell(x+2, 2, ' ', termbox.ColorDefault, termbox.ColorDefault)
+	}
 
-		termbox.SetCell(cursorX, 2, '_', termbox.ColorGreen|termbox.AttrBold, termbox.ColorDefault)
+	SetLine(2, 2, string(input), termbox.ColorYellow, termbox.ColorDefault)
 
-		DrawVerticalBorders()
-		termbox.Flush()
+	cursorX := 2
+	for i := 0; i < cursorPos; i++ {
+		cursorX += runewidth.RuneWidth(input[i])
 	}
+
+	termbox.SetCell(cursorX, 2, '_', termbox.ColorGreen|termbox.AttrBold, termbox.ColorDefault)
+
+	DrawVerticalBorders()
 }
 
 func SetLine(x, y int, msg string, fg, bg termbox.Attribute) {
@@ -146,3 +176,71 @@ func SetLine(x, y int, msg string, fg, bg termbox.Attribute) {
 		x += runewidth.RuneWidth(c)
 	}
 }
+
+// ShowLines displays a scrollable list of lines with a title and waits for the user.
+// Returns true if the user pressed Enter and false if the user pressed Esc
+func ShowLines(title string, lines []string) bool {
+
+	scrollOffset := 0
+
+	for {
+		termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
+		_, height := termbox.Size()
+		visibleRows := height - 3
+
+		if visibleRows < 1 {
+			visibleRows = 1
+		}
+
+		scrollOffset = clampScroll(scrollOffset, len(lines), visibleRows)
+
+		end := scrollOffset + visibleRows
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		for i := scrollOffset; i < end; i++ {
+			SetLine(2, i-scrollOffset+2, lines[i], termbox.ColorWhite, termbox.ColorDefault)
+		}
+
+		DrawVerticalBorders()
+		DrawHeader("DeckBuilder v0.1.2")
+		PrintHotkeyBar(title, true)
+		PrintHotkeyBar("  ▲/  ▼- scroll; Enter - continue; Esc - back.", false)
+		termbox.Flush()
+
+		switch ev := termbox.PollEvent(); ev.Type {
+		case termbox.EventKey:
+			switch ev.Key {
+			case termbox.KeyArrowUp:
+				scrollOffset--
+			case termbox.KeyArrowDown:
+				scrollOffset++
+			case termbox.KeyPgup:
+				scrollOffset -= visibleRows
+			case termbox.KeyPgdn:
+				scrollOffset += visibleRows
+			case termbox.KeyEnter:
+				return true
+			case termbox.KeyEsc:
+				return false
+			}
+		case termbox.EventInterrupt, termbox.EventError:
+			return false
+		}
+	}
+}
+
+// clampScroll keeps the scroll offset of a list of total lines within bounds
+func clampScroll(offset, total, visibleRows int) int {
+
+	if offset > total-visibleRows {
+		offset = total - visibleRows
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+
+	return offset
+}
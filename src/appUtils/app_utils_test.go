@@ -46,3 +46,21 @@ func TestGetInputLogic(t *testing.T) {
 		GetInput("prompt", false)
 	})
 }
+
+func TestClampScroll(t *testing.T) {
+
+	cases := []struct {
+		offset, total, visible, want int
+	}{
+		{-1, 10, 5, 0},
+		{3, 10, 5, 3},
+		{8, 10, 5, 5},
+		{2, 3, 5, 0},
+	}
+
+	for _, c := range cases {
+		if got := clampScroll(c.offset, c.total, c.visible); got != c.want {
+			t.Errorf("clampScroll(%d, %d, %d) = %d, want %d", c.offset, c.total, c.visible, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package dataFrame
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// SetAppendMode enables or disables append mode. In append mode AddRowAndSave and
+// AddUniqueRowAndSave write only the new record to the end of the file instead of
+// rewriting it, which keeps adding words to large decks fast
+func (df *DataFrame) SetAppendMode(enabled bool) {
+	df.appendMode = enabled
+}
+
+// saveAddedRow saves the DataFrame after row has been added to it
+func (df *DataFrame) saveAddedRow(row []string, filePath string) error {
+
+	if !df.appendMode || df.tx != nil {
+		return df.saveChanges(filePath)
+	}
+
+	return df.AppendCSV(row, filePath)
+}
+
+// AppendCSV writes row to the end of the CSV file without rewriting it.
+// If the file does not start with the BOM and header of the DataFrame or does not
+// end with a newline, the whole DataFrame is saved with SaveCSV instead
+func (df *DataFrame) AppendCSV(row []string, filePath string) error {
+
+	filePath, err := getTrueFilepath(filePath)
+
+	if err != nil {
+		return err
+	}
+
+	// Changes made in a transaction are written by Commit
+	if df.tx != nil {
+		return ErrInTransaction
+	}
+
+	if err := df.checkDelimiter(); err != nil {
+		return err
+	}
+
+	if err := df.CheckFile(filePath); err != nil {
+		return err
+	}
+
+	ok, err := df.canAppend(filePath)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return df.SaveCSV(filePath)
+	}
+
+	record := df.appendRecord(nil, row)
+
+	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(record); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+
+	df.markSaved()
+
+	// The appended file stays the file the DataFrame was loaded from
+	if fp := df.fingerprint; fp != nil && fp.path == filePath {
+		if info, err := file.Stat(); err == nil {
+			fp.hash.Write(record)
+			fp.stat(info)
+		}
+	}
+
+	return file.Close()
+}
+
+// canAppend reports whether the file starts with the header the DataFrame would
+// write and ends with a newline, so that a record can be appended to it
+func (df *DataFrame) canAppend(filePath string) (bool, error) {
+
+	// Records are appended in UTF-8 only, to a file that is already in UTF-8
+	if df.encoding != EncodingUTF8 || df.outputEncoding() != EncodingUTF8 {
+		return false, nil
+	}
+
+	header := df.encodeHeader()
+
+	file, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	prefix := make([]byte, len(header))
+	if _, err := io.ReadFull(file, prefix); err != nil {
+		// The file is shorter than the header
+		return false, nil
+	}
+
+	if !bytes.Equal(prefix, header) {
+		return false, nil
+	}
+
+	// The header ends with a newline, so only a longer file needs checking
+	info, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	if info.Size() == int64(len(header)) {
+		return true, nil
+	}
+
+	last := make([]byte, 1)
+	if _, err := file.ReadAt(last, info.Size()-1); err != nil {
+		return false, err
+	}
+
+	return last[0] == '\n', nil
+}
@@ -0,0 +1,96 @@
+package dataFrame
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAppendCSV_appendsRecord(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "deck.csv")
+
+	df := NewDataFrame(';')
+	df.Columns = []string{"Word", "Translation"}
+	df.Data = [][]string{{"cat", "кошка"}}
+
+	if err := df.SaveCSV(file); err != nil {
+		t.Fatal(err)
+	}
+
+	df.SetAppendMode(true)
+
+	if err := df.AddRowAndSave([]string{"semi;colon", `say "hi"`}, file); err != nil {
+		t.Fatalf("AddRowAndSave error: %v", err)
+	}
+
+	data, _ := os.ReadFile(file)
+	want := "Word;Translation\ncat;кошка\n\"semi;colon\";\"say \"\"hi\"\"\"\n"
+	if string(data) != want {
+		t.Errorf("Unexpected contents:\n%q\nwant\n%q", data, want)
+	}
+
+	df2 := NewDataFrame(';')
+	if err := df2.LoadCSV(file); err != nil {
+		t.Fatalf("LoadCSV error: %v", err)
+	}
+
+	if len(df2.Data) != 2 || df2.Data[1][0] != "semi;colon" {
+		t.Errorf("Appended row mismatch: %v", df2.Data)
+	}
+}
+
+func TestAppendCSV_fallsBackToRewrite(t *testing.T) {
+
+	cases := map[string]string{
+		"no trailing newline": "Word;Translation\ncat;кошка",
+		"other header":        "Term;Meaning\ncat;кошка\n",
+		"missing file":        "",
+	}
+
+	for name, contents := range cases {
+
+		file := filepath.Join(t.TempDir(), "deck.csv")
+
+		if contents != "" {
+			if err := os.WriteFile(file, []byte(contents), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		df := NewDataFrame(';')
+		df.Columns = []string{"Word", "Translation"}
+		df.Data = [][]string{{"cat", "кошка"}}
+		df.SetAppendMode(true)
+
+		if err := df.AddRowAndSave([]string{"dog", "собака"}, file); err != nil {
+			t.Fatalf("%s: AddRowAndSave error: %v", name, err)
+		}
+
+		data, _ := os.ReadFile(file)
+		if string(data) != "Word;Translation\ncat;кошка\ndog;собака\n" {
+			t.Errorf("%s: expected full rewrite, got %q", name, data)
+		}
+	}
+}
+
+func TestAddUniqueRowAndSave_appendSkipsDuplicate(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "deck.csv")
+
+	df := NewDataFrame(';')
+	df.Columns = []string{"Word", "Translation"}
+	_ = df.SaveCSV(file)
+	df.SetAppendMode(true)
+
+	for i := 0; i < 2; i++ {
+		if err := df.AddUniqueRowAndSave([]string{"cat", "кошка"}, file); err != nil {
+			t.Fatalf("AddUniqueRowAndSave error: %v", err)
+		}
+	}
+
+	data, _ := os.ReadFile(file)
+	if string(data) != "Word;Translation\ncat;кошка\n" {
+		t.Errorf("Unexpected contents: %q", data)
+	}
+}
@@ -0,0 +1,78 @@
+package dataFrame
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// defaultFileMode is the mode of files that did not exist before saving
+const defaultFileMode os.FileMode = 0644
+
+// WriteFileAtomic writes a file through a temporary file in the same directory
+// which is synced and renamed over filePath, so that a crash or a failed write
+// never leaves a truncated file behind. The mode of an existing file is preserved
+func WriteFileAtomic(filePath string, write func(w io.Writer) error) (err error) {
+
+	// Replace the target of a symlink rather than the link itself
+	if resolved, err := filepath.EvalSymlinks(filePath); err == nil {
+		filePath = resolved
+	}
+
+	mode := defaultFileMode
+	if info, err := os.Stat(filePath); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	dir := filepath.Dir(filePath)
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(filePath)+".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	// Remove the temporary file if anything goes wrong before the rename
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if err = write(tmp); err != nil {
+		return err
+	}
+
+	if err = tmp.Chmod(mode); err != nil {
+		return err
+	}
+
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Rename(tmp.Name(), filePath); err != nil {
+		return err
+	}
+
+	syncDir(dir)
+
+	return nil
+}
+
+// syncDir flushes a directory entry to disk so that a rename survives a crash.
+// Not every platform supports syncing directories, so errors are ignored
+func syncDir(dir string) {
+
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	defer d.Close()
+
+	d.Sync()
+}
@@ -0,0 +1,96 @@
+package dataFrame
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic_keepsOriginalOnError(t *testing.T) {
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "deck.csv")
+
+	if err := os.WriteFile(file, []byte("Word;Translation\ncat;кошка\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := WriteFileAtomic(file, func(w io.Writer) error {
+		io.WriteString(w, "partial")
+		return errors.New("disk full")
+	})
+
+	if err == nil {
+		t.Fatal("Expected write error to be returned")
+	}
+
+	data, _ := os.ReadFile(file)
+	if string(data) != "Word;Translation\ncat;кошка\n" {
+		t.Errorf("Original file was modified: %q", data)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected temporary file to be removed, got %d entries", len(entries))
+	}
+}
+
+func TestSaveCSV_preservesModeAndReplaces(t *testing.T) {
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "deck.csv")
+
+	if err := os.WriteFile(file, []byte("old\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	df := NewDataFrame(';')
+	df.Columns = []string{"Word", "Translation"}
+	df.Data = [][]string{{"cat", "кошка"}}
+
+	if err := df.SaveCSV(file); err != nil {
+		t.Fatalf("SaveCSV error: %v", err)
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600, got %v", info.Mode().Perm())
+	}
+
+	data, _ := os.ReadFile(file)
+	if string(data) != "Word;Translation\ncat;кошка\n" {
+		t.Errorf("Unexpected contents: %q", data)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected only the deck in dir, got %d entries", len(entries))
+	}
+}
+
+func TestSaveCSV_newFileMode(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "new.csv")
+
+	df := NewDataFrame(';')
+	df.Columns = []string{"Word"}
+
+	if err := df.SaveCSV(file); err != nil {
+		t.Fatalf("SaveCSV error: %v", err)
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Mode().Perm() != defaultFileMode {
+		t.Errorf("Expected mode %v, got %v", defaultFileMode, info.Mode().Perm())
+	}
+}
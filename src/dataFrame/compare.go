@@ -0,0 +1,139 @@
+package dataFrame
+
+import (
+	"errors"
+	"strings"
+)
+
+// Comparison maps a cell value to the form used to compare it with other values:
+// two values are considered equal when their comparison forms are equal
+type Comparison func(value string) string
+
+var (
+	// CompareExact compares values byte by byte
+	CompareExact Comparison = func(value string) string {
+		return value
+	}
+
+	// CompareFold compares values ignoring case and surrounding spaces
+	CompareFold Comparison = func(value string) string {
+		return strings.ToLower(strings.TrimSpace(value))
+	}
+
+	// CompareNormalized compares values ignoring case, repeated spaces and
+	// the difference between composed and decomposed letters
+	CompareNormalized Comparison = func(value string) string {
+		return strings.ToLower(strings.Join(strings.Fields(NFC(value)), " "))
+	}
+)
+
+// keySeparator joins the values of several key columns into one key
+const keySeparator = "\x1f"
+
+// SetKey sets the columns that identify a row and the comparison used for them.
+// The key is used by AddUniqueRow, FindDuplicate, HasKey and FindRowsByKey
+func (df *DataFrame) SetKey(comparison Comparison, columns ...string) error {
+
+	if len(columns) == 0 {
+		return errors.New("no key columns specified")
+	}
+
+	for _, name := range columns {
+		if df.columnIndex(name) == -1 {
+			return errors.New("column name not found")
+		}
+	}
+
+	if comparison == nil {
+		comparison = CompareExact
+	}
+
+	df.keyColumns = columns
+	df.comparison = comparison
+
+	return df.rebuildIndex()
+}
+
+// KeyColumns returns the names of the key columns, or nil if no key is set
+func (df *DataFrame) KeyColumns() []string {
+	return df.keyColumns
+}
+
+// keyIndexes returns the indexes of the key columns in df.Columns
+func (df *DataFrame) keyIndexes() ([]int, error) {
+
+	if len(df.keyColumns) == 0 {
+		return nil, errors.New("no key columns set")
+	}
+
+	indexes := make([]int, len(df.keyColumns))
+
+	for i, name := range df.keyColumns {
+		indexes[i] = df.columnIndex(name)
+		if indexes[i] == -1 {
+			return nil, errors.New("key column not found")
+		}
+	}
+
+	return indexes, nil
+}
+
+// makeKey joins the comparison forms of values into a single key
+func (df *DataFrame) makeKey(values []string) string {
+
+	parts := make([]string, len(values))
+
+	for i, v := range values {
+		parts[i] = df.comparison(v)
+	}
+
+	return strings.Join(parts, keySeparator)
+}
+
+// rowKey returns the key of a row built from the key columns
+func (df *DataFrame) rowKey(row []string, indexes []int) string {
+
+	values := make([]string, len(indexes))
+
+	for i, idx := range indexes {
+		if idx < len(row) {
+			values[i] = row[idx]
+		}
+	}
+
+	return df.makeKey(values)
+}
+
+// FindDuplicate returns the index of the first row with the same key as row,
+// or -1 if there is no such row
+func (df *DataFrame) FindDuplicate(row []string) (int, error) {
+
+	indexes, err := df.keyIndexes()
+	if err != nil {
+		return -1, err
+	}
+
+	rows := df.findRows(df.rowKey(row, indexes), indexes)
+
+	if len(rows) == 0 {
+		return -1, nil
+	}
+
+	return rows[0], nil
+}
+
+// HasKey reports whether a row with the given key values exists.
+// Values are given in the order of the key columns
+func (df *DataFrame) HasKey(values ...string) (bool, error) {
+
+	indexes, err := df.keyIndexes()
+	if err != nil {
+		return false, err
+	}
+
+	if len(values) != len(indexes) {
+		return false, errors.New("values length does not match number of key columns")
+	}
+
+	return len(df.findRows(df.makeKey(values), indexes)) > 0, nil
+}
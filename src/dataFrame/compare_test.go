@@ -0,0 +1,97 @@
+package dataFrame
+
+import "testing"
+
+func TestComparisons(t *testing.T) {
+
+	if CompareExact("Hello") == CompareExact("hello") {
+		t.Error("CompareExact should be case-sensitive")
+	}
+
+	if CompareFold(" Hello ") != CompareFold("hello") {
+		t.Error("CompareFold should ignore case and surrounding spaces")
+	}
+
+	if CompareNormalized("Cafe\u0301  au  lait") != CompareNormalized("café au lait") {
+		t.Error("CompareNormalized should ignore composition and repeated spaces")
+	}
+}
+
+func TestSetKey(t *testing.T) {
+
+	df := NewDataFrame(';')
+	df.Columns = []string{"Word", "Translation"}
+
+	if err := df.SetKey(CompareFold, "Missing"); err == nil {
+		t.Error("Expected error for unknown key column")
+	}
+
+	if err := df.SetKey(CompareFold); err == nil {
+		t.Error("Expected error for empty key")
+	}
+
+	if err := df.SetKey(nil, "Word"); err != nil {
+		t.Fatalf("SetKey error: %v", err)
+	}
+
+	if len(df.KeyColumns()) != 1 || df.KeyColumns()[0] != "Word" {
+		t.Errorf("KeyColumns mismatch: %v", df.KeyColumns())
+	}
+}
+
+func TestFindDuplicateAndHasKey(t *testing.T) {
+
+	df := NewDataFrame(';')
+	df.Columns = []string{"Word", "Translation"}
+	df.Data = [][]string{{"cat", "кошка"}, {"dog", "собака"}}
+
+	if _, err := df.HasKey("cat"); err == nil {
+		t.Error("Expected error when no key is set")
+	}
+
+	_ = df.SetKey(CompareFold, "Word")
+
+	idx, err := df.FindDuplicate([]string{"DOG", "пёс"})
+	if err != nil {
+		t.Fatalf("FindDuplicate error: %v", err)
+	}
+
+	if idx != 1 {
+		t.Errorf("Expected duplicate at 1, got %d", idx)
+	}
+
+	// Translations and the header are not part of the key
+	for _, value := range []string{"кошка", "Word"} {
+		if ok, _ := df.HasKey(value); ok {
+			t.Errorf("HasKey(%q) should be false", value)
+		}
+	}
+
+	if _, err := df.HasKey("cat", "кошка"); err == nil {
+		t.Error("Expected error for wrong number of key values")
+	}
+}
+
+func TestAddUniqueRowWithKey(t *testing.T) {
+
+	df := NewDataFrame(';')
+	df.Columns = []string{"Word", "Translation"}
+	df.Data = [][]string{{"cat", "кошка"}}
+	_ = df.SetKey(CompareFold, "Word")
+
+	if err := df.AddUniqueRow([]string{"Cat", "кот"}); err != nil {
+		t.Fatalf("AddUniqueRow error: %v", err)
+	}
+
+	if len(df.Data) != 1 {
+		t.Errorf("Expected duplicate key to be rejected, got %v", df.Data)
+	}
+
+	if err := df.AddUniqueRow([]string{"кошка", "cat"}); err != nil {
+		t.Fatalf("AddUniqueRow error: %v", err)
+	}
+
+	if len(df.Data) != 2 {
+		t.Errorf("Expected new key to be added, got %v", df.Data)
+	}
+}
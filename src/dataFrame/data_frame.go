@@ -1,28 +1,61 @@
 package dataFrame
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
 	"encoding/csv"
 	"errors"
+	"io"
 	"os"
 	"os/user"
 	"path/filepath"
+	"slices"
 	"strings"
+	"unicode/utf8"
 )
 
 // DataFrame is a structure for storing tabular data
 type DataFrame struct {
-	Columns   []string   // column names
-	Data      [][]string // rows of data (each row is a slice of strings)
-	delimiter rune       // field delimiter (e.g. ',', ';', '\t')
+	Columns          []string     // column names
+	Data             [][]string   // rows of data (each row is a slice of strings)
+	delimiter        rune         // field delimiter (e.g. ',', ';', '\t')
+	dialect          Dialect      // quoting, BOM and line endings of the file
+	sniff            bool         // detect the delimiter on load (see AutoDelimiter)
+	keyColumns       []string     // columns that identify a row (see SetKey)
+	comparison       Comparison   // policy used to compare key values
+	appendMode       bool         // write added rows to the end of the file (see SetAppendMode)
+	indexed          bool         // whether the key index is enabled (see EnableIndex)
+	index            rowIndex     // key index, nil if disabled
+	journal          *Journal     // history of changes for undo and redo, nil if not recorded
+	tx               *transaction // open transaction, nil if none (see Begin)
+	directives       []Directive  // Anki header lines of the file (see Directives)
+	headerFormat     HeaderFormat // how the beginning of the file is written
+	columnDirectives []Directive  // directives that hold column numbers (see SetColumnDirective)
+	encoding         Encoding     // character encoding of the file
+	saveEncoding     Encoding     // encoding the file is saved in, empty to keep the loaded one
+	fingerprint      *fingerprint // state of the file when it was last loaded or saved (see CheckFile)
+	pending          []Operation  // changes made since the file was last loaded or saved (see MergeCSV)
+	saved            journalState // history when the file was last loaded or saved (see DiscardChanges)
 }
 
-// NewDataFrame creates an empty DataFrame with a specified delimiter
+// NewDataFrame creates an empty DataFrame with a specified delimiter.
+// With AutoDelimiter the delimiter is detected when the file is loaded
 func NewDataFrame(delimiter rune) *DataFrame {
 	return &DataFrame{
 		delimiter: delimiter,
+		dialect:   Dialect{Delimiter: delimiter},
+		sniff:     delimiter == AutoDelimiter,
+		encoding:  EncodingUTF8,
 	}
 }
 
+// Delimiter returns the field delimiter, or AutoDelimiter if it is not detected yet
+func (df *DataFrame) Delimiter() rune {
+	return df.delimiter
+}
+
 // getTrueFilepath return filepath with /home/{user}/... if used ~/...
 func getTrueFilepath(filePath string) (string, error) {
 
@@ -68,6 +101,12 @@ func CreateNewCSV(filePath string, columns []string, delimiter rune) error {
 
 // LoadCSV loads data from a CSV file into the DataFrame
 func (df *DataFrame) LoadCSV(filePath string) error {
+	return df.LoadCSVContext(context.Background(), filePath)
+}
+
+// LoadCSVContext loads data from a CSV file into the DataFrame, stopping when ctx is done.
+// The DataFrame is changed only if the whole file has been loaded
+func (df *DataFrame) LoadCSVContext(ctx context.Context, filePath string) error {
 
 	filePath, err := getTrueFilepath(filePath)
 
@@ -81,25 +120,106 @@ func (df *DataFrame) LoadCSV(filePath string) error {
 	}
 	defer file.Close()
 
-	reader := csv.NewReader(file)
-	reader.Comma = df.delimiter
-	records, err := reader.ReadAll()
+	info, raw, err := readFile(file)
+	if err != nil {
+		return err
+	}
+
+	encoding := DetectEncoding(raw)
+
+	data, err := decode(raw, encoding)
 	if err != nil {
 		return err
 	}
 
-	if len(records) == 0 {
+	bom := bytes.HasPrefix(data, utf8BOM)
+	directives, body, _ := splitDirectives(bytes.TrimPrefix(data, utf8BOM))
+
+	// The delimiter is detected only in auto mode, the quoting style always
+	delimiter := df.delimiter
+	if df.sniff {
+		delimiter = AutoDelimiter
+	}
+
+	// A #separator directive names the delimiter of the file
+	if i := findDirective(directives, DirectiveSeparator); i != -1 {
+		if r := parseSeparator(directives[i].Value); r != AutoDelimiter {
+			delimiter = r
+		}
+	}
+
+	dialect := sniffDialect(body, delimiter)
+	dialect.BOM = bom
+
+	reader := csv.NewReader(bytes.NewReader(body))
+	reader.Comma = dialect.Delimiter
+	reader.LazyQuotes = dialect.LazyQuotes
+	reader.FieldsPerRecord = -1
+
+	records := [][]string{}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		records = append(records, record)
+
+		if len(records)%contextCheckRows == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var columns []string
+
+	// With a #columns directive the file has no header row
+	if i := findDirective(directives, DirectiveColumns); i != -1 {
+		columns = splitFields(directives[i].Value, dialect.Delimiter)
+	} else if len(records) == 0 {
 		return errors.New("csv file is empty")
+	} else {
+		columns, records = records[0], records[1:]
 	}
 
-	df.Columns = records[0]
-	df.Data = records[1:]
+	// Rows with missing fields get empty values, so that they can be edited like the others.
+	// Extra fields are kept (see Validate for a report of both)
+	for i, record := range records {
+		if len(record) < len(columns) {
+			records[i] = append(record, make([]string, len(columns)-len(record))...)
+		}
+	}
 
-	return nil
+	df.encoding = encoding
+	df.dialect = dialect
+	df.delimiter = dialect.Delimiter
+	df.directives = directives
+	df.Columns = columns
+	df.Data = records
+	df.fingerprint = newFingerprint(filePath, info, raw)
+	df.markSaved()
+
+	return df.rebuildIndex()
 }
 
-// SaveCSV saves the DataFrame to a CSV file
+// SaveCSV saves the DataFrame to a CSV file in the dialect it was loaded with.
+// The file is replaced atomically, so a failed save leaves the previous contents intact
 func (df *DataFrame) SaveCSV(filePath string) error {
+	return df.SaveCSVContext(context.Background(), filePath)
+}
+
+// SaveCSVContext saves the DataFrame to a CSV file, stopping when ctx is done.
+// A cancelled save leaves the previous contents of the file intact
+func (df *DataFrame) SaveCSVContext(ctx context.Context, filePath string) error {
 
 	filePath, err := getTrueFilepath(filePath)
 
@@ -107,29 +227,132 @@ func (df *DataFrame) SaveCSV(filePath string) error {
 		return err
 	}
 
-	file, err := os.Create(filePath)
+	// Changes made in a transaction are written by Commit
+	if df.tx != nil {
+		return ErrInTransaction
+	}
+
+	if err := df.checkDelimiter(); err != nil {
+		return err
+	}
+
+	if err := df.CheckFile(filePath); err != nil {
+		return err
+	}
+
+	// A deck converted to UTF-8 does not keep the byte order mark of UTF-16
+	output := df.outputEncoding()
+	if output != df.encoding && output == EncodingUTF8 {
+		df.dialect.BOM = false
+	}
+
+	hash := sha256.New()
+
+	err = WriteFileAtomic(filePath, func(w io.Writer) error {
+		return df.writeCSV(ctx, io.MultiWriter(w, hash))
+	})
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
-	writer := csv.NewWriter(file)
-	writer.Comma = df.delimiter
-	defer writer.Flush()
+	df.encoding = output
+	df.markSaved()
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		df.fingerprint = nil
+		return nil
+	}
+
+	df.fingerprint = &fingerprint{path: filePath, hash: hash}
+	df.fingerprint.stat(info)
+
+	return nil
+}
+
+// checkDelimiter makes sure the DataFrame has a delimiter that can be written.
+// A DataFrame in auto mode that was never loaded is written with ';'
+func (df *DataFrame) checkDelimiter() error {
+
+	if df.delimiter == AutoDelimiter {
+		df.delimiter = defaultDelimiter
+		df.dialect.Delimiter = defaultDelimiter
+	}
+
+	if df.delimiter == '"' || df.delimiter == '\r' || df.delimiter == '\n' ||
+		!utf8.ValidRune(df.delimiter) || df.delimiter == utf8.RuneError {
+		return errors.New("invalid delimiter")
+	}
+
+	return nil
+}
+
+// writeCSV writes the column names and data rows to w in the output encoding
+func (df *DataFrame) writeCSV(ctx context.Context, w io.Writer) error {
+
+	encoding := df.outputEncoding()
+	if encoding == EncodingUTF8 {
+		return df.writeUTF8(ctx, w)
+	}
+
+	var buf bytes.Buffer
+	if err := df.writeUTF8(ctx, &buf); err != nil {
+		return err
+	}
+
+	data, err := encode(buf.Bytes(), encoding)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+
+	return err
+}
+
+// writeUTF8 writes the column names and data rows to w in UTF-8
+func (df *DataFrame) writeUTF8(ctx context.Context, w io.Writer) error {
+
+	writer := bufio.NewWriter(w)
 
-	// Write column names
-	if err := writer.Write(df.Columns); err != nil {
+	if _, err := writer.Write(df.encodeHeader()); err != nil {
 		return err
 	}
 
-	// Write data rows
-	for _, row := range df.Data {
-		if err := writer.Write(row); err != nil {
+	var buf []byte
+
+	for i, row := range df.Data {
+
+		if i%contextCheckRows == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
+		buf = df.appendRecord(buf[:0], row)
+		if _, err := writer.Write(buf); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return writer.Flush()
+}
+
+// encodeHeader returns the beginning of the file up to and including the column names
+func (df *DataFrame) encodeHeader() []byte {
+
+	var header []byte
+
+	if df.dialect.BOM {
+		header = append(header, utf8BOM...)
+	}
+
+	header, hasColumns := df.appendDirectives(header)
+	if hasColumns {
+		return header
+	}
+
+	return df.appendRecord(header, df.Columns)
 }
 
 // AddColumn adds a column to df
@@ -155,7 +378,18 @@ func (df *DataFrame) AddRow(row []string) error {
 		return errors.New("row length does not match number of columns")
 	}
 
+	return df.appendRow(row)
+}
+
+// appendRow records a checked row in the journal and appends it to df.Data
+func (df *DataFrame) appendRow(row []string) error {
+
+	if err := df.record(Operation{Kind: OpAdd, Index: len(df.Data), Row: slices.Clone(row)}); err != nil {
+		return err
+	}
+
 	df.Data = append(df.Data, row)
+	df.indexAddedRow()
 
 	return nil
 }
@@ -167,16 +401,31 @@ func (df *DataFrame) AddRowAndSave(row []string, filepath string) error {
 		return err
 	}
 
-	return df.SaveCSV(filepath)
+	return df.saveAddedRow(row, filepath)
 }
 
-// AddUniqueRow adds a row only if it is not already in the DataFrame
+// AddUniqueRow adds a row only if it is not already in the DataFrame.
+// If a key is set, rows are compared by their key columns, otherwise by all values
 func (df *DataFrame) AddUniqueRow(row []string) error {
 
 	if len(row) != len(df.Columns) {
 		return errors.New("row length does not match number of columns")
 	}
 
+	if len(df.keyColumns) > 0 {
+
+		idx, err := df.FindDuplicate(row)
+		if err != nil {
+			return err
+		}
+
+		if idx == -1 {
+			return df.appendRow(row)
+		}
+
+		return nil
+	}
+
 	for _, existing := range df.Data {
 
 		duplicate := true
@@ -193,20 +442,28 @@ func (df *DataFrame) AddUniqueRow(row []string) error {
 		}
 	}
 
-	df.Data = append(df.Data, row)
-
-	return nil
+	return df.appendRow(row)
 }
 
 // AddUniqueRowAndSave adds a row only if it is not already in the DataFrame
 // and saves the DataFrame to a CSV file
 func (df *DataFrame) AddUniqueRowAndSave(row []string, filepath string) error {
 
+	count := len(df.Data)
+
 	if err := df.AddUniqueRow(row); err != nil {
 		return err
 	}
 
-	return df.SaveCSV(filepath)
+	if len(df.Data) == count {
+		// The row is a duplicate, the file is already up to date
+		if df.appendMode {
+			return nil
+		}
+		return df.saveChanges(filepath)
+	}
+
+	return df.saveAddedRow(row, filepath)
 }
 
 // DeleteRow deletes a row by its index
@@ -216,9 +473,14 @@ func (df *DataFrame) DeleteRow(index int) error {
 		return errors.New("index out of range")
 	}
 
+	if err := df.record(Operation{Kind: OpDelete, Index: index, Old: slices.Clone(df.Data[index])}); err != nil {
+		return err
+	}
+
 	df.Data = append(df.Data[:index], df.Data[index+1:]...)
 
-	return nil
+	// Rows after the deleted one have shifted, so the index is rebuilt
+	return df.rebuildIndex()
 }
 
 // DeleteRowAndSave deletes the row by index and saves the DataFrame to a CSV file
@@ -228,31 +490,26 @@ func (df *DataFrame) DeleteRowAndSave(index int, filePath string) error {
 		return err
 	}
 
-	return df.SaveCSV(filePath)
+	return df.saveChanges(filePath)
 }
 
-// DeleteRowByColumnValue deletes the first row by value in the specified column
+// DeleteRowByColumnValue deletes the first row by value in the specified column.
+// Returns ErrNoMatch if no row has this value (see DeleteRowsWhere to delete all of them)
 func (df *DataFrame) DeleteRowByColumnValue(columnName, value string) error {
 
-	colIdx := -1
-
-	for i, name := range df.Columns {
-		if name == columnName {
-			colIdx = i
-			break
-		}
+	indexes, err := df.Find(columnName, value)
+	if err != nil {
+		return err
 	}
 
-	if colIdx == -1 {
-		return errors.New("column name not found")
+	if len(indexes) == 0 {
+		return ErrNoMatch
 	}
 
-	err := df.DeleteRow(colIdx)
-
-	return err
+	return df.DeleteRow(indexes[0])
 }
 
-// DeleteRowByColumnValueAndSave deletes the row by index and saves the DataFrame to a CSV file
+// DeleteRowByColumnValueAndSave deletes the first row by value in the specified column
 // and saves the DataFrame to a CSV file
 func (df *DataFrame) DeleteRowByColumnValueAndSave(columnName, value, filePath string) error {
 
@@ -260,7 +517,19 @@ func (df *DataFrame) DeleteRowByColumnValueAndSave(columnName, value, filePath s
 		return err
 	}
 
-	return df.SaveCSV(filePath)
+	return df.saveChanges(filePath)
+}
+
+// columnIndex returns the index of the column with the given name, or -1
+func (df *DataFrame) columnIndex(name string) int {
+
+	for i, n := range df.Columns {
+		if n == name {
+			return i
+		}
+	}
+
+	return -1
 }
 
 // getColumnByIndex returns all column values by index
@@ -286,14 +555,7 @@ func (df *DataFrame) getColumnByIndex(idx int) ([]string, error) {
 // GetColumnByName returns all column values by name
 func (df *DataFrame) GetColumnByName(name string) ([]string, error) {
 
-	var idx = -1
-
-	for i, n := range df.Columns {
-		if n == name {
-			idx = i
-			break
-		}
-	}
+	idx := df.columnIndex(name)
 
 	if idx == -1 {
 		return nil, errors.New("column name not found")
@@ -197,6 +197,41 @@ func TestCSVSaveLoad(t *testing.T) {
 	}
 }
 
+func TestLoadCSV_wrongFieldCount(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "ragged.csv")
+	if err := os.WriteFile(file, []byte("Word;Translation\ncat\ndog;собака;пёс\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	df := NewDataFrame(';')
+	if err := df.LoadCSV(file); err != nil {
+		t.Fatalf("LoadCSV error: %v", err)
+	}
+
+	want := [][]string{{"cat", ""}, {"dog", "собака", "пёс"}}
+	if !reflect.DeepEqual(df.Data, want) {
+		t.Errorf("Expected short rows padded and long rows kept, got %q", df.Data)
+	}
+
+	// A padded row can be changed and new rows added
+	if err := df.UpdateRow(0, []string{"cat", "кошка"}); err != nil {
+		t.Errorf("UpdateRow error: %v", err)
+	}
+
+	if err := df.AddRow([]string{"bird", "птица"}); err != nil {
+		t.Errorf("AddRow error: %v", err)
+	}
+
+	// A row with extra fields does not keep changes to the other rows from being committed
+	_ = df.Begin()
+	_ = df.UpdateRow(2, []string{"bird", "птичка"})
+
+	if err := df.Commit(file); err != nil {
+		t.Errorf("Commit error: %v", err)
+	}
+}
+
 func TestCreateNewCSV(t *testing.T) {
 
 	file := filepath.Join(os.TempDir(), "testnew.csv")
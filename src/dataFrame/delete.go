@@ -0,0 +1,64 @@
+package dataFrame
+
+import (
+	"errors"
+	"slices"
+)
+
+// ErrNoMatch is returned when no row matches the condition of a deletion
+var ErrNoMatch = errors.New("no rows matched")
+
+// DeleteRowsWhere deletes every row whose value in column satisfies match and returns
+// the deleted rows in their original order; len(removed) is the number of deleted rows.
+// If no row matches, nothing is deleted and ErrNoMatch is returned
+func (df *DataFrame) DeleteRowsWhere(column string, match Predicate) (removed [][]string, err error) {
+
+	indexes, err := df.FindWhere(column, match)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(indexes) == 0 {
+		return nil, ErrNoMatch
+	}
+
+	// Deletions are recorded one after another, so each index accounts
+	// for the rows deleted before it
+	ops := make([]Operation, len(indexes))
+	for k, idx := range indexes {
+		ops[k] = Operation{Kind: OpDelete, Index: idx - k, Old: slices.Clone(df.Data[idx])}
+	}
+
+	if err := df.record(ops...); err != nil {
+		return nil, err
+	}
+
+	kept := make([][]string, 0, len(df.Data)-len(indexes))
+	removed = make([][]string, 0, len(indexes))
+	next := 0
+
+	for i, row := range df.Data {
+		if next < len(indexes) && indexes[next] == i {
+			removed = append(removed, row)
+			next++
+		} else {
+			kept = append(kept, row)
+		}
+	}
+
+	df.Data = kept
+
+	return removed, df.rebuildIndex()
+}
+
+// DeleteRowsWhereAndSave deletes every row whose value in column satisfies match
+// and saves the DataFrame to a CSV file. The file is not written if nothing matched
+func (df *DataFrame) DeleteRowsWhereAndSave(column string, match Predicate, filePath string) ([][]string, error) {
+
+	removed, err := df.DeleteRowsWhere(column, match)
+	if err != nil {
+		return nil, err
+	}
+
+	return removed, df.saveChanges(filePath)
+}
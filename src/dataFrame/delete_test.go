@@ -0,0 +1,85 @@
+package dataFrame
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDeleteRowsWhere(t *testing.T) {
+
+	df := NewDataFrame(';')
+	df.Columns = []string{"Word", "Translation"}
+	df.Data = [][]string{{"cat", "кошка"}, {"dog", "собака"}, {"cat", "кот"}, {"bird", ""}}
+	_ = df.SetKey(CompareExact, "Word")
+	_ = df.EnableIndex()
+
+	removed, err := df.DeleteRowsWhere("Word", Equals("cat"))
+	if err != nil {
+		t.Fatalf("DeleteRowsWhere error: %v", err)
+	}
+
+	if !reflect.DeepEqual(removed, [][]string{{"cat", "кошка"}, {"cat", "кот"}}) {
+		t.Errorf("Unexpected removed rows: %v", removed)
+	}
+
+	if !reflect.DeepEqual(df.Data, [][]string{{"dog", "собака"}, {"bird", ""}}) {
+		t.Errorf("Unexpected remaining rows: %v", df.Data)
+	}
+
+	if rows, _ := df.FindRowsByKey("bird"); !reflect.DeepEqual(rows, []int{1}) {
+		t.Errorf("Expected index to be rebuilt, got %v", rows)
+	}
+
+	removed, err = df.DeleteRowsWhere("Translation", IsEmpty())
+	if err != nil || len(removed) != 1 {
+		t.Errorf("Expected one row deleted by predicate, got %v, %v", removed, err)
+	}
+
+	if _, err := df.DeleteRowsWhere("Word", Equals("fish")); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("Expected ErrNoMatch, got %v", err)
+	}
+
+	if _, err := df.DeleteRowsWhere("Missing", IsEmpty()); err == nil {
+		t.Error("Expected error for unknown column")
+	}
+}
+
+func TestDeleteRowByColumnValue_usesValue(t *testing.T) {
+
+	df := NewDataFrame(';')
+	df.Columns = []string{"Option"}
+	df.Data = [][]string{{"a.csv"}, {"b.csv"}, {"c.csv"}}
+
+	if err := df.DeleteRowByColumnValue("Option", "c.csv"); err != nil {
+		t.Fatalf("DeleteRowByColumnValue error: %v", err)
+	}
+
+	if !reflect.DeepEqual(df.Data, [][]string{{"a.csv"}, {"b.csv"}}) {
+		t.Errorf("Wrong row deleted: %v", df.Data)
+	}
+
+	if err := df.DeleteRowByColumnValue("Option", "c.csv"); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("Expected ErrNoMatch, got %v", err)
+	}
+}
+
+func TestDeleteRowsWhereAndSave_noMatchKeepsFile(t *testing.T) {
+
+	file := writeTestFile(t, "Option\na.csv\n")
+
+	df := NewDataFrame(';')
+	_ = df.LoadCSV(file)
+	df.Data = append(df.Data, []string{"unsaved.csv"})
+
+	if _, err := df.DeleteRowsWhereAndSave("Option", Equals("x.csv"), file); !errors.Is(err, ErrNoMatch) {
+		t.Fatalf("Expected ErrNoMatch, got %v", err)
+	}
+
+	df2 := NewDataFrame(';')
+	_ = df2.LoadCSV(file)
+
+	if len(df2.Data) != 1 {
+		t.Errorf("File should not be written when nothing matched: %v", df2.Data)
+	}
+}
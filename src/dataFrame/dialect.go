@@ -0,0 +1,262 @@
+package dataFrame
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+)
+
+// AutoDelimiter makes LoadCSV detect the delimiter of the file (see SniffDialect)
+const AutoDelimiter rune = 0
+
+// defaultDelimiter is used when the delimiter of a file cannot be detected
+const defaultDelimiter = ';'
+
+// sniffLines is the number of lines SniffDialect looks at
+const sniffLines = 20
+
+// candidateDelimiters are the delimiters SniffDialect chooses from, in order of preference
+var candidateDelimiters = []rune{';', ',', '\t', '|'}
+
+// Dialect describes how a CSV file is written
+type Dialect struct {
+	Delimiter  rune // field delimiter
+	QuoteAll   bool // every field is enclosed in quotes
+	LazyQuotes bool // quotes may appear inside unquoted fields
+	BOM        bool // the file starts with a UTF-8 byte order mark
+	CRLF       bool // lines end with \r\n
+}
+
+// SniffDialect detects the dialect of a CSV file from its first lines.
+// The delimiter is the candidate that appears the same non-zero number of times
+// outside quotes on the most lines; ';' is assumed if none does
+func SniffDialect(data []byte) Dialect {
+	return sniffDialect(data, AutoDelimiter)
+}
+
+// sniffDialect detects the dialect of a CSV file. If delimiter is not AutoDelimiter,
+// it is used as is and only the quoting style, BOM and line endings are detected
+func sniffDialect(data []byte, delimiter rune) Dialect {
+
+	d := Dialect{Delimiter: delimiter}
+
+	if bytes.HasPrefix(data, utf8BOM) {
+		d.BOM = true
+		data = data[len(utf8BOM):]
+	}
+
+	lines := sampleLines(data)
+
+	if len(lines) > 0 && strings.HasSuffix(lines[0], "\r") {
+		d.CRLF = true
+	}
+
+	for i := range lines {
+		lines[i] = strings.TrimSuffix(lines[i], "\r")
+	}
+
+	if d.Delimiter == AutoDelimiter {
+		d.Delimiter = detectDelimiter(lines)
+	}
+
+	d.QuoteAll, d.LazyQuotes = quotingStyle(lines, d.Delimiter)
+
+	return d
+}
+
+// detectDelimiter returns the candidate delimiter that splits the lines most consistently
+func detectDelimiter(lines []string) rune {
+
+	delimiter := rune(defaultDelimiter)
+	bestScore, bestCount := 0, 0
+
+	for _, candidate := range candidateDelimiters {
+
+		score, count := delimiterScore(lines, candidate)
+
+		if score > bestScore || (score == bestScore && score > 0 && count > bestCount) {
+			delimiter = candidate
+			bestScore, bestCount = score, count
+		}
+	}
+
+	return delimiter
+}
+
+// sampleLines returns up to sniffLines non-empty lines of data.
+// Newlines inside quoted fields do not start a new line
+func sampleLines(data []byte) []string {
+
+	var lines []string
+	var line strings.Builder
+	inQuotes := false
+
+	for len(data) > 0 && len(lines) < sniffLines {
+
+		r, size := utf8.DecodeRune(data)
+		data = data[size:]
+
+		if r == '"' {
+			inQuotes = !inQuotes
+		}
+
+		if r == '\n' && !inQuotes {
+			if line.Len() > 0 {
+				lines = append(lines, line.String())
+			}
+			line.Reset()
+			continue
+		}
+
+		line.WriteRune(r)
+	}
+
+	if line.Len() > 0 && len(lines) < sniffLines {
+		lines = append(lines, line.String())
+	}
+
+	return lines
+}
+
+// delimiterScore returns the number of lines on which delimiter appears as often
+// as on the first line, and that number of occurrences
+func delimiterScore(lines []string, delimiter rune) (int, int) {
+
+	if len(lines) == 0 {
+		return 0, 0
+	}
+
+	count := len(splitFields(lines[0], delimiter)) - 1
+	if count == 0 {
+		return 0, 0
+	}
+
+	score := 0
+
+	for _, line := range lines {
+		if len(splitFields(line, delimiter))-1 == count {
+			score++
+		}
+	}
+
+	return score, count
+}
+
+// quotingStyle reports whether every field of the sample is quoted and whether
+// quotes appear inside unquoted fields
+func quotingStyle(lines []string, delimiter rune) (quoteAll bool, lazyQuotes bool) {
+
+	quoteAll = len(lines) > 0
+
+	for _, line := range lines {
+		for _, field := range splitFields(line, delimiter) {
+
+			quoted := len(field) >= 2 && field[0] == '"' && field[len(field)-1] == '"'
+
+			if !quoted {
+				quoteAll = false
+
+				if strings.Contains(field, `"`) {
+					lazyQuotes = true
+				}
+			}
+		}
+	}
+
+	return quoteAll, lazyQuotes
+}
+
+// splitFields splits a line into raw fields, ignoring delimiters inside quotes
+func splitFields(line string, delimiter rune) []string {
+
+	var fields []string
+	start := 0
+	inQuotes := false
+
+	for i, r := range line {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == delimiter && !inQuotes:
+			fields = append(fields, line[start:i])
+			start = i + utf8.RuneLen(r)
+		}
+	}
+
+	return append(fields, line[start:])
+}
+
+// Dialect returns the dialect the DataFrame is read and written with
+func (df *DataFrame) Dialect() Dialect {
+	return df.dialect
+}
+
+// SetDialect sets the dialect used by SaveCSV and AppendCSV.
+// A zero delimiter makes LoadCSV detect the dialect of the file
+func (df *DataFrame) SetDialect(d Dialect) {
+	df.dialect = d
+	df.delimiter = d.Delimiter
+	df.sniff = d.Delimiter == AutoDelimiter
+}
+
+// appendRecord appends a CSV record to buf, quoting fields the way encoding/csv does,
+// or every field if the dialect requires it
+func (df *DataFrame) appendRecord(buf []byte, fields []string) []byte {
+
+	for i, field := range fields {
+
+		if i > 0 {
+			buf = utf8.AppendRune(buf, df.delimiter)
+		}
+
+		if !df.dialect.QuoteAll && !fieldNeedsQuotes(field, df.delimiter) {
+			buf = append(buf, field...)
+			continue
+		}
+
+		buf = append(buf, '"')
+
+		// Bytes are copied as is, so invalid UTF-8 is not replaced
+		for i := 0; i < len(field); i++ {
+			switch c := field[i]; c {
+			case '"':
+				buf = append(buf, `""`...)
+			case '\n':
+				if df.dialect.CRLF {
+					buf = append(buf, '\r')
+				}
+				buf = append(buf, '\n')
+			case '\r':
+				if !df.dialect.CRLF {
+					buf = append(buf, '\r')
+				}
+			default:
+				buf = append(buf, c)
+			}
+		}
+
+		buf = append(buf, '"')
+	}
+
+	if df.dialect.CRLF {
+		buf = append(buf, '\r')
+	}
+
+	return append(buf, '\n')
+}
+
+// fieldNeedsQuotes reports whether a field must be quoted, following encoding/csv
+func fieldNeedsQuotes(field string, delimiter rune) bool {
+
+	if field == "" {
+		return false
+	}
+
+	if field == `\.` || strings.ContainsRune(field, delimiter) || strings.ContainsAny(field, "\"\r\n") {
+		return true
+	}
+
+	r, _ := utf8.DecodeRuneInString(field)
+
+	return r == ' ' || r == '\t'
+}
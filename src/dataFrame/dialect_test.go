@@ -0,0 +1,117 @@
+package dataFrame
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSniffDialect_delimiters(t *testing.T) {
+
+	cases := map[string]rune{
+		"Word;Translation\ncat;кошка\n":             ';',
+		"Word,Translation\ncat,\"кошка, кот\"\n":    ',',
+		"Word\tTranslation\ncat\tкошка\n":           '\t',
+		"Word|Translation\ncat|кошка\ndog|собака\n": '|',
+		"Word\ncat\n": ';',
+		"":            ';',
+		// Commas inside translations do not win over the consistent ';'
+		"Word;Translation\ncat;кошка, кот\ndog;собака\n": ';',
+	}
+
+	for data, want := range cases {
+		if got := SniffDialect([]byte(data)).Delimiter; got != want {
+			t.Errorf("SniffDialect(%q) delimiter = %q, want %q", data, got, want)
+		}
+	}
+}
+
+func TestSniffDialect_quotingBOMAndLineEndings(t *testing.T) {
+
+	d := SniffDialect([]byte("\xEF\xBB\xBF\"Word\",\"Translation\"\r\n\"cat\",\"кошка\"\r\n"))
+
+	if d.Delimiter != ',' || !d.QuoteAll || !d.BOM || !d.CRLF || d.LazyQuotes {
+		t.Errorf("Unexpected dialect: %+v", d)
+	}
+
+	d = SniffDialect([]byte("Word;Translation\n12\" disk;диск\n"))
+
+	if !d.LazyQuotes || d.QuoteAll {
+		t.Errorf("Expected lazy quotes, got %+v", d)
+	}
+}
+
+func TestLoadCSV_autoDelimiterRoundtrip(t *testing.T) {
+
+	contents := "\xEF\xBB\xBF\"Word\",\"Translation\"\r\n\"cat\",\"кошка\"\r\n"
+	file := writeTestFile(t, contents)
+
+	df := NewDataFrame(AutoDelimiter)
+	if err := df.LoadCSV(file); err != nil {
+		t.Fatalf("LoadCSV error: %v", err)
+	}
+
+	if df.Delimiter() != ',' || len(df.Columns) != 2 || df.Data[0][1] != "кошка" {
+		t.Fatalf("Unexpected frame: %q %v %v", df.Delimiter(), df.Columns, df.Data)
+	}
+
+	if err := df.SaveCSV(file); err != nil {
+		t.Fatalf("SaveCSV error: %v", err)
+	}
+
+	data, _ := os.ReadFile(file)
+	if string(data) != contents {
+		t.Errorf("Dialect not preserved:\n%q\nwant\n%q", data, contents)
+	}
+
+	df.SetAppendMode(true)
+	if err := df.AddRowAndSave([]string{"dog", "собака"}, file); err != nil {
+		t.Fatalf("AddRowAndSave error: %v", err)
+	}
+
+	data, _ = os.ReadFile(file)
+	if string(data) != contents+"\"dog\",\"собака\"\r\n" {
+		t.Errorf("Unexpected append in dialect: %q", data)
+	}
+}
+
+func TestLoadCSV_overrideDelimiter(t *testing.T) {
+
+	file := writeTestFile(t, "Word,Translation\ncat,кошка\n")
+
+	df := NewDataFrame(';')
+	if err := df.LoadCSV(file); err != nil {
+		t.Fatalf("LoadCSV error: %v", err)
+	}
+
+	if len(df.Columns) != 1 || df.Delimiter() != ';' {
+		t.Errorf("Expected forced ';' delimiter, got %q %v", df.Delimiter(), df.Columns)
+	}
+}
+
+func TestSaveCSV_autoDelimiterDefaultsToSemicolon(t *testing.T) {
+
+	file := writeTestFile(t, "")
+
+	df := NewDataFrame(AutoDelimiter)
+	df.Columns = []string{"Word", "Translation"}
+
+	if err := df.SaveCSV(file); err != nil {
+		t.Fatalf("SaveCSV error: %v", err)
+	}
+
+	data, _ := os.ReadFile(file)
+	if string(data) != "Word;Translation\n" {
+		t.Errorf("Unexpected contents: %q", data)
+	}
+}
+
+func TestAppendRecord_matchesEncodingCSV(t *testing.T) {
+
+	df := NewDataFrame(';')
+	got := string(df.appendRecord(nil, []string{"plain", "a;b", `q"q`, " lead", "", "multi\nline"}))
+	want := "plain;\"a;b\";\"q\"\"q\";\" lead\";;\"multi\nline\"\n"
+
+	if got != want {
+		t.Errorf("appendRecord = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,227 @@
+package dataFrame
+
+import (
+	"bytes"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Keys of the Anki file header directives
+const (
+	DirectiveSeparator = "separator"
+	DirectiveHTML      = "html"
+	DirectiveColumns   = "columns"
+	DirectiveNotetype  = "notetype"
+	DirectiveDeck      = "deck"
+	DirectiveTags      = "tags"
+
+	// The values of these directives are 1-based column numbers (see SetColumnDirective)
+	DirectiveTagsColumn     = "tags column"
+	DirectiveDeckColumn     = "deck column"
+	DirectiveNotetypeColumn = "notetype column"
+)
+
+// Directive is an Anki file header line such as "#separator:Semicolon".
+// Anki 2.1.55+ reads them from the start of a text file before the notes
+type Directive struct {
+	Key   string
+	Value string
+}
+
+// HeaderFormat selects how SaveCSV writes the beginning of the file
+type HeaderFormat int
+
+const (
+	HeaderAsLoaded HeaderFormat = iota // keep the directives the file was loaded with
+	HeaderPlain                        // a header row with the column names only
+	HeaderAnki                         // Anki directives, with the column names in #columns
+)
+
+// separatorNames are the names Anki uses for delimiters in #separator
+var separatorNames = map[rune]string{
+	',':  "Comma",
+	';':  "Semicolon",
+	'\t': "Tab",
+	' ':  "Space",
+	'|':  "Pipe",
+	':':  "Colon",
+}
+
+// splitDirectives splits the directive lines off the start of data.
+// Returns the directives, the rest of the data and the number of lines removed
+func splitDirectives(data []byte) ([]Directive, []byte, int) {
+
+	var directives []Directive
+	lines := 0
+
+	for bytes.HasPrefix(data, []byte("#")) {
+
+		line, rest, _ := bytes.Cut(data, []byte("\n"))
+
+		key, value, ok := strings.Cut(strings.TrimSuffix(string(line[1:]), "\r"), ":")
+		if !ok || key == "" {
+			break
+		}
+
+		directives = append(directives, Directive{Key: key, Value: value})
+		data = rest
+		lines++
+	}
+
+	return directives, data, lines
+}
+
+// parseSeparator returns the delimiter named in a #separator directive,
+// or AutoDelimiter if the name is unknown
+func parseSeparator(value string) rune {
+
+	for r, name := range separatorNames {
+		if strings.EqualFold(name, value) {
+			return r
+		}
+	}
+
+	if utf8.RuneCountInString(value) == 1 {
+		r, _ := utf8.DecodeRuneInString(value)
+		return r
+	}
+
+	return AutoDelimiter
+}
+
+// findDirective returns the index of the directive with key, or -1
+func findDirective(directives []Directive, key string) int {
+	return slices.IndexFunc(directives, func(d Directive) bool { return d.Key == key })
+}
+
+// Directives returns the Anki header directives the file was loaded with or that were set
+func (df *DataFrame) Directives() []Directive {
+	return slices.Clone(df.directives)
+}
+
+// Directive returns the value of a directive and whether it is set
+func (df *DataFrame) Directive(key string) (string, bool) {
+
+	if i := findDirective(df.directives, key); i != -1 {
+		return df.directives[i].Value, true
+	}
+
+	return "", false
+}
+
+// SetDirective sets the value of a directive. An empty value removes it.
+// The values of #separator and #columns are always written from the delimiter and columns
+func (df *DataFrame) SetDirective(key, value string) {
+
+	i := findDirective(df.directives, key)
+
+	switch {
+	case value == "" && i != -1:
+		df.directives = slices.Delete(df.directives, i, i+1)
+	case value == "":
+	case i != -1:
+		df.directives[i].Value = value
+	default:
+		df.directives = append(df.directives, Directive{Key: key, Value: value})
+	}
+}
+
+// SetColumnDirective makes the directive key (e.g. DirectiveTagsColumn) point to column.
+// The column number is written whenever the directive is set or the header format is
+// HeaderAnki, and the directive is dropped if the DataFrame has no such column
+func (df *DataFrame) SetColumnDirective(key, column string) {
+
+	if i := findDirective(df.columnDirectives, key); i != -1 {
+		df.columnDirectives[i].Value = column
+		return
+	}
+
+	df.columnDirectives = append(df.columnDirectives, Directive{Key: key, Value: column})
+}
+
+// SetHeaderFormat sets how SaveCSV writes the beginning of the file
+func (df *DataFrame) SetHeaderFormat(format HeaderFormat) {
+	df.headerFormat = format
+}
+
+// headerDirectives returns the directives to write according to the header format
+func (df *DataFrame) headerDirectives() []Directive {
+
+	if df.headerFormat == HeaderPlain {
+		return nil
+	}
+
+	directives := slices.Clone(df.directives)
+
+	if df.headerFormat == HeaderAnki {
+		if findDirective(directives, DirectiveHTML) == -1 {
+			directives = slices.Insert(directives, 0, Directive{Key: DirectiveHTML, Value: "false"})
+		}
+		if findDirective(directives, DirectiveSeparator) == -1 {
+			directives = slices.Insert(directives, 0, Directive{Key: DirectiveSeparator})
+		}
+	}
+
+	for _, cd := range df.columnDirectives {
+
+		idx := df.columnIndex(cd.Value)
+		i := findDirective(directives, cd.Key)
+
+		switch {
+		case idx == -1 && i != -1:
+			directives = slices.Delete(directives, i, i+1)
+		case idx == -1:
+		case i != -1:
+			directives[i].Value = strconv.Itoa(idx + 1)
+		case df.headerFormat == HeaderAnki:
+			directives = append(directives, Directive{Key: cd.Key, Value: strconv.Itoa(idx + 1)})
+		}
+	}
+
+	if df.headerFormat == HeaderAnki && findDirective(directives, DirectiveColumns) == -1 {
+		directives = append(directives, Directive{Key: DirectiveColumns})
+	}
+
+	for i, d := range directives {
+		switch d.Key {
+		case DirectiveSeparator:
+			name, ok := separatorNames[df.delimiter]
+			if !ok {
+				name = string(df.delimiter)
+			}
+			directives[i].Value = name
+		case DirectiveColumns:
+			directives[i].Value = strings.Join(df.Columns, string(df.delimiter))
+		}
+	}
+
+	return directives
+}
+
+// appendDirectives appends the directive lines to buf.
+// Returns false if there is no #columns directive, so a header row is needed
+func (df *DataFrame) appendDirectives(buf []byte) ([]byte, bool) {
+
+	hasColumns := false
+
+	for _, d := range df.headerDirectives() {
+
+		buf = append(buf, '#')
+		buf = append(buf, d.Key...)
+		buf = append(buf, ':')
+		buf = append(buf, d.Value...)
+
+		if df.dialect.CRLF {
+			buf = append(buf, '\r')
+		}
+		buf = append(buf, '\n')
+
+		if d.Key == DirectiveColumns {
+			hasColumns = true
+		}
+	}
+
+	return buf, hasColumns
+}
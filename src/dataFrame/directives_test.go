@@ -0,0 +1,172 @@
+package dataFrame
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestLoadCSV_directives(t *testing.T) {
+
+	contents := "#separator:Tab\n#html:true\n#tags:english\n#columns:Word\tTranslation\ncat\tкошка\ndog\tсобака\n"
+	file := writeTestFile(t, contents)
+
+	df := NewDataFrame(AutoDelimiter)
+	if err := df.LoadCSV(file); err != nil {
+		t.Fatalf("LoadCSV error: %v", err)
+	}
+
+	if df.Delimiter() != '\t' {
+		t.Errorf("Expected tab delimiter, got %q", df.Delimiter())
+	}
+
+	if !reflect.DeepEqual(df.Columns, []string{"Word", "Translation"}) {
+		t.Errorf("Columns not read from #columns: %v", df.Columns)
+	}
+
+	expected := [][]string{{"cat", "кошка"}, {"dog", "собака"}}
+	if !reflect.DeepEqual(df.Data, expected) {
+		t.Errorf("Expected %v, got %v", expected, df.Data)
+	}
+
+	if value, ok := df.Directive(DirectiveTags); !ok || value != "english" {
+		t.Errorf("Expected #tags:english, got %q", value)
+	}
+
+	if err := df.SaveCSV(file); err != nil {
+		t.Fatalf("SaveCSV error: %v", err)
+	}
+
+	if data, _ := os.ReadFile(file); string(data) != contents {
+		t.Errorf("Directives not preserved:\n%s", data)
+	}
+}
+
+func TestLoadCSV_directivesWithHeaderRow(t *testing.T) {
+
+	contents := "#html:false\n#deck:Words\nWord;Translation\ncat;кошка\n"
+	file := writeTestFile(t, contents)
+
+	df := NewDataFrame(AutoDelimiter)
+	_ = df.LoadCSV(file)
+
+	if !reflect.DeepEqual(df.Columns, []string{"Word", "Translation"}) || len(df.Data) != 1 {
+		t.Errorf("Unexpected data: %v %v", df.Columns, df.Data)
+	}
+
+	_ = df.AddRowAndSave([]string{"dog", "собака"}, file)
+
+	if data, _ := os.ReadFile(file); string(data) != contents+"dog;собака\n" {
+		t.Errorf("Directives not preserved:\n%s", data)
+	}
+}
+
+func TestSetHeaderFormat(t *testing.T) {
+
+	file := writeTestFile(t, "Word;Translation\ncat;кошка\n")
+
+	df := NewDataFrame(AutoDelimiter)
+	_ = df.LoadCSV(file)
+	df.SetHeaderFormat(HeaderAnki)
+	df.SetAppendMode(true)
+
+	if err := df.AddRowAndSave([]string{"dog", "собака"}, file); err != nil {
+		t.Fatalf("AddRowAndSave error: %v", err)
+	}
+
+	anki := "#separator:Semicolon\n#html:false\n#columns:Word;Translation\ncat;кошка\ndog;собака\n"
+	if data, _ := os.ReadFile(file); string(data) != anki {
+		t.Errorf("Unexpected Anki format:\n%s", data)
+	}
+
+	// Appending keeps the directives
+	_ = df.AddRowAndSave([]string{"fox", "лиса"}, file)
+
+	df2 := NewDataFrame(AutoDelimiter)
+	_ = df2.LoadCSV(file)
+
+	if len(df2.Data) != 3 || !reflect.DeepEqual(df2.Columns, df.Columns) {
+		t.Errorf("Unexpected data after append: %v %v", df2.Columns, df2.Data)
+	}
+
+	df2.SetHeaderFormat(HeaderPlain)
+	_ = df2.SaveCSV(file)
+
+	if data, _ := os.ReadFile(file); string(data) != "Word;Translation\ncat;кошка\ndog;собака\nfox;лиса\n" {
+		t.Errorf("Directives not removed:\n%s", data)
+	}
+}
+
+func TestSetDirective(t *testing.T) {
+
+	df := NewDataFrame(';')
+
+	df.SetDirective(DirectiveTags, "english")
+	df.SetDirective(DirectiveDeck, "Words")
+	df.SetDirective(DirectiveTags, "english verbs")
+	df.SetDirective(DirectiveDeck, "")
+
+	expected := []Directive{{Key: DirectiveTags, Value: "english verbs"}}
+	if !reflect.DeepEqual(df.Directives(), expected) {
+		t.Errorf("Expected %v, got %v", expected, df.Directives())
+	}
+}
+
+func TestParseSeparator(t *testing.T) {
+
+	tests := map[string]rune{"Semicolon": ';', "comma": ',', "TAB": '\t', "|": '|', "Unknown": AutoDelimiter}
+
+	for value, expected := range tests {
+		if r := parseSeparator(value); r != expected {
+			t.Errorf("parseSeparator(%q) = %q, expected %q", value, r, expected)
+		}
+	}
+}
+
+func TestValidate_directives(t *testing.T) {
+
+	file := writeTestFile(t, "#separator:Comma\n#columns:Word,Translation\ncat,кошка\ncat,кот\n")
+
+	report, err := NewDataFrame(';').Validate(file, []string{"Word", "Translation"})
+	if err != nil {
+		t.Fatalf("Validate error: %v", err)
+	}
+
+	if report.Rows != 2 || len(report.Issues) != 1 {
+		t.Fatalf("Unexpected report: %v", report.Lines())
+	}
+
+	if issue := report.Issues[0]; issue.Kind != IssueDuplicateKey || issue.Line != 4 {
+		t.Errorf("Expected duplicate on line 4, got %v", issue)
+	}
+}
+
+func TestSetColumnDirective(t *testing.T) {
+
+	df := NewDataFrame(';')
+	df.Columns = []string{"Word", "Translation", "Tags"}
+	df.SetColumnDirective(DirectiveTagsColumn, "Tags")
+	df.SetColumnDirective(DirectiveDeckColumn, "Deck")
+
+	// Written only in the Anki format or when the file has it
+	if header := string(df.encodeHeader()); header != "Word;Translation;Tags\n" {
+		t.Errorf("Unexpected plain header %q", header)
+	}
+
+	df.SetHeaderFormat(HeaderAnki)
+
+	expected := "#separator:Semicolon\n#html:false\n#tags column:3\n#columns:Word;Translation;Tags\n"
+	if header := string(df.encodeHeader()); header != expected {
+		t.Errorf("Expected %q, got %q", expected, header)
+	}
+
+	// The column number follows the column
+	df.SetHeaderFormat(HeaderAsLoaded)
+	df.SetDirective(DirectiveTagsColumn, "5")
+	df.Columns = []string{"Tags", "Word", "Translation"}
+
+	expected = "#tags column:1\nTags;Word;Translation\n"
+	if header := string(df.encodeHeader()); header != expected {
+		t.Errorf("Expected %q, got %q", expected, header)
+	}
+}
@@ -0,0 +1,213 @@
+package dataFrame
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Encoding is a character encoding of a deck file
+type Encoding string
+
+const (
+	EncodingUTF8        Encoding = "UTF-8"
+	EncodingUTF16LE     Encoding = "UTF-16LE"
+	EncodingUTF16BE     Encoding = "UTF-16BE"
+	EncodingWindows1251 Encoding = "Windows-1251"
+	EncodingKOI8R       Encoding = "KOI8-R"
+)
+
+// Byte order marks of UTF-16 files
+var (
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+// singleByteCharmaps are the supported single-byte encodings
+var singleByteCharmaps = map[Encoding]*charmap.Charmap{
+	EncodingWindows1251: charmap.Windows1251,
+	EncodingKOI8R:       charmap.KOI8R,
+}
+
+// DetectEncoding detects the encoding of a deck file. A byte order mark decides it
+// if present; otherwise NUL bytes mean UTF-16, valid UTF-8 means UTF-8, and other
+// data is taken as the Cyrillic single-byte encoding that gives more lowercase letters
+func DetectEncoding(data []byte) Encoding {
+
+	switch {
+	case bytes.HasPrefix(data, utf8BOM):
+		return EncodingUTF8
+	case bytes.HasPrefix(data, utf16LEBOM):
+		return EncodingUTF16LE
+	case bytes.HasPrefix(data, utf16BEBOM):
+		return EncodingUTF16BE
+	}
+
+	// Text in other encodings has no NUL bytes, while ASCII characters in UTF-16
+	// have a zero high byte
+	even, odd := 0, 0
+	for i, b := range data {
+		if b == 0 {
+			if i%2 == 0 {
+				even++
+			} else {
+				odd++
+			}
+		}
+	}
+
+	if even+odd > 0 {
+		if odd >= even {
+			return EncodingUTF16LE
+		}
+		return EncodingUTF16BE
+	}
+
+	if looksUTF8(data) {
+		return EncodingUTF8
+	}
+
+	if cyrillicScore(data, charmap.KOI8R) > cyrillicScore(data, charmap.Windows1251) {
+		return EncodingKOI8R
+	}
+
+	return EncodingWindows1251
+}
+
+// looksUTF8 reports whether data is UTF-8, allowing a few invalid bytes
+// in a file that is mostly valid multi-byte UTF-8
+func looksUTF8(data []byte) bool {
+
+	multiByte, invalid := 0, 0
+
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		switch {
+		case r == utf8.RuneError && size == 1:
+			invalid++
+		case size > 1:
+			multiByte++
+		}
+		data = data[size:]
+	}
+
+	return invalid == 0 || multiByte > invalid
+}
+
+// cyrillicScore returns the number of lowercase Cyrillic letters data has in an encoding.
+// Lowercase letters are more frequent in text, and the two encodings swap the cases
+func cyrillicScore(data []byte, cm *charmap.Charmap) int {
+
+	score := 0
+
+	for _, b := range data {
+		if b >= 0x80 {
+			if r := cm.DecodeByte(b); (r >= 'а' && r <= 'я') || r == 'ё' {
+				score++
+			}
+		}
+	}
+
+	return score
+}
+
+// decode converts data from encoding to UTF-8. A UTF-16 byte order mark
+// becomes a UTF-8 one, so that the dialect keeps it
+func decode(data []byte, encoding Encoding) ([]byte, error) {
+
+	switch encoding {
+	case EncodingUTF8, "":
+		return data, nil
+	case EncodingUTF16LE, EncodingUTF16BE:
+		if len(data)%2 != 0 {
+			return nil, errors.New("UTF-16 data has an odd number of bytes")
+		}
+
+		units := make([]uint16, len(data)/2)
+		for i := range units {
+			if encoding == EncodingUTF16LE {
+				units[i] = uint16(data[2*i]) | uint16(data[2*i+1])<<8
+			} else {
+				units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+			}
+		}
+
+		return []byte(string(utf16.Decode(units))), nil
+	}
+
+	cm, ok := singleByteCharmaps[encoding]
+	if !ok {
+		return nil, fmt.Errorf("unsupported encoding %s", encoding)
+	}
+
+	return cm.NewDecoder().Bytes(data)
+}
+
+// encode converts UTF-8 data to encoding. Returns an error if a character
+// cannot be represented in a single-byte encoding
+func encode(data []byte, encoding Encoding) ([]byte, error) {
+
+	switch encoding {
+	case EncodingUTF8, "":
+		return data, nil
+	case EncodingUTF16LE, EncodingUTF16BE:
+		units := utf16.Encode([]rune(string(data)))
+		result := make([]byte, 0, len(units)*2)
+
+		for _, u := range units {
+			if encoding == EncodingUTF16LE {
+				result = append(result, byte(u), byte(u>>8))
+			} else {
+				result = append(result, byte(u>>8), byte(u))
+			}
+		}
+
+		return result, nil
+	}
+
+	cm, ok := singleByteCharmaps[encoding]
+	if !ok {
+		return nil, fmt.Errorf("unsupported encoding %s", encoding)
+	}
+
+	// Single-byte files have no byte order mark
+	data = bytes.TrimPrefix(data, utf8BOM)
+	result := make([]byte, 0, len(data))
+
+	for _, r := range string(data) {
+
+		b, ok := cm.EncodeRune(r)
+		if !ok || r == utf8.RuneError {
+			return nil, fmt.Errorf("%q cannot be saved in %s", r, encoding)
+		}
+
+		result = append(result, b)
+	}
+
+	return result, nil
+}
+
+// Encoding returns the encoding the file was loaded in
+func (df *DataFrame) Encoding() Encoding {
+	return df.encoding
+}
+
+// SetSaveEncoding sets the encoding SaveCSV writes the file in.
+// An empty encoding keeps the encoding the file was loaded in
+func (df *DataFrame) SetSaveEncoding(encoding Encoding) {
+	df.saveEncoding = encoding
+}
+
+// outputEncoding returns the encoding the file is written in
+func (df *DataFrame) outputEncoding() Encoding {
+
+	if df.saveEncoding != "" {
+		return df.saveEncoding
+	}
+
+	return df.encoding
+}
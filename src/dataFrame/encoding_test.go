@@ -0,0 +1,144 @@
+package dataFrame
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// deckText is a small deck used to test the encodings
+const deckText = "Word;Translation\ncat;кошка\ndog;собака\nhedgehog;ёж\n"
+
+func TestDetectEncoding(t *testing.T) {
+
+	utf16le, _ := encode([]byte(deckText), EncodingUTF16LE)
+	utf16be, _ := encode([]byte(deckText), EncodingUTF16BE)
+	cp1251, _ := encode([]byte(deckText), EncodingWindows1251)
+	koi8, _ := encode([]byte(deckText), EncodingKOI8R)
+
+	tests := []struct {
+		name string
+		data []byte
+		want Encoding
+	}{
+		{"UTF-8", []byte(deckText), EncodingUTF8},
+		{"UTF-8 BOM", slices.Concat(utf8BOM, []byte(deckText)), EncodingUTF8},
+		{"UTF-16LE BOM", slices.Concat(utf16LEBOM, utf16le), EncodingUTF16LE},
+		{"UTF-16BE BOM", slices.Concat(utf16BEBOM, utf16be), EncodingUTF16BE},
+		{"UTF-16LE", utf16le, EncodingUTF16LE},
+		{"UTF-16BE", utf16be, EncodingUTF16BE},
+		{"Windows-1251", cp1251, EncodingWindows1251},
+		{"KOI8-R", koi8, EncodingKOI8R},
+		{"UTF-8 with a bad byte", []byte("Word;Translation\ncat;кошка\nbad\xff;x\n"), EncodingUTF8},
+	}
+
+	for _, tt := range tests {
+		if got := DetectEncoding(tt.data); got != tt.want {
+			t.Errorf("%s: got %s", tt.name, got)
+		}
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+
+	for _, encoding := range []Encoding{EncodingUTF16LE, EncodingUTF16BE, EncodingWindows1251, EncodingKOI8R} {
+
+		encoded, err := encode([]byte(deckText), encoding)
+		if err != nil {
+			t.Fatalf("%s: encode error: %v", encoding, err)
+		}
+
+		decoded, err := decode(encoded, encoding)
+		if err != nil {
+			t.Fatalf("%s: decode error: %v", encoding, err)
+		}
+
+		if string(decoded) != deckText {
+			t.Errorf("%s: round trip gave %q", encoding, decoded)
+		}
+	}
+
+	if _, err := encode([]byte("日本"), EncodingWindows1251); err == nil {
+		t.Error("Expected error for a character missing from Windows-1251")
+	}
+
+	if _, err := decode([]byte{0x41}, EncodingUTF16LE); err == nil {
+		t.Error("Expected error for odd UTF-16 data")
+	}
+}
+
+func TestLoadCSV_encodings(t *testing.T) {
+
+	expected := [][]string{{"cat", "кошка"}, {"dog", "собака"}, {"hedgehog", "ёж"}}
+
+	for _, encoding := range []Encoding{EncodingUTF16LE, EncodingWindows1251, EncodingKOI8R} {
+
+		data, _ := encode([]byte(deckText), encoding)
+		if encoding == EncodingUTF16LE {
+			data = slices.Concat(utf16LEBOM, data)
+		}
+
+		file := writeTestFile(t, string(data))
+
+		df := NewDataFrame(AutoDelimiter)
+		if err := df.LoadCSV(file); err != nil {
+			t.Fatalf("%s: LoadCSV error: %v", encoding, err)
+		}
+
+		if df.Encoding() != encoding || !reflect.DeepEqual(df.Data, expected) {
+			t.Errorf("%s: got %s %v", encoding, df.Encoding(), df.Data)
+		}
+
+		// Saved back in the original encoding, also when a row is added
+		df.SetAppendMode(true)
+		_ = df.AddRowAndSave([]string{"fox", "лиса"}, file)
+
+		saved, _ := os.ReadFile(file)
+		want, _ := encode([]byte(deckText+"fox;лиса\n"), encoding)
+		if encoding == EncodingUTF16LE {
+			want = slices.Concat(utf16LEBOM, want)
+		}
+
+		if !bytes.Equal(saved, want) {
+			t.Errorf("%s: not saved in the original encoding: %q", encoding, saved)
+		}
+	}
+}
+
+func TestSetSaveEncoding(t *testing.T) {
+
+	data, _ := encode([]byte(deckText), EncodingUTF16LE)
+	file := writeTestFile(t, string(slices.Concat(utf16LEBOM, data)))
+
+	df := NewDataFrame(AutoDelimiter)
+	_ = df.LoadCSV(file)
+	df.SetSaveEncoding(EncodingUTF8)
+
+	if err := df.SaveCSV(file); err != nil {
+		t.Fatalf("SaveCSV error: %v", err)
+	}
+
+	if saved, _ := os.ReadFile(file); string(saved) != deckText {
+		t.Errorf("Deck not converted to UTF-8: %q", saved)
+	}
+
+	if df.Encoding() != EncodingUTF8 {
+		t.Errorf("Expected UTF-8 after conversion, got %s", df.Encoding())
+	}
+}
+
+func TestValidate_encoding(t *testing.T) {
+
+	data, _ := encode([]byte(deckText), EncodingWindows1251)
+
+	report, err := NewDataFrame(';').Validate(writeTestFile(t, string(data)), []string{"Word", "Translation"})
+	if err != nil {
+		t.Fatalf("Validate error: %v", err)
+	}
+
+	if !report.OK() || report.Encoding != EncodingWindows1251 || report.Rows != 3 {
+		t.Errorf("Unexpected report: %s %d %v", report.Encoding, report.Rows, report.Lines())
+	}
+}
@@ -0,0 +1,215 @@
+package dataFrame
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"os"
+	"slices"
+	"time"
+)
+
+// ErrFileChanged is returned by SaveCSV and AppendCSV when the file was changed
+// outside of the DataFrame since it was loaded or last saved
+var ErrFileChanged = errors.New("the file was changed outside of DeckBuilder")
+
+// fingerprint is the state of a file when the DataFrame last loaded or saved it
+type fingerprint struct {
+	path    string
+	modTime time.Time
+	size    int64
+	hash    hash.Hash // SHA-256 of the contents, updated when records are appended
+}
+
+// newFingerprint returns the fingerprint of the file at path with the given contents
+func newFingerprint(path string, info os.FileInfo, data []byte) *fingerprint {
+
+	fp := &fingerprint{path: path, hash: sha256.New()}
+	fp.hash.Write(data)
+	fp.stat(info)
+
+	return fp
+}
+
+// stat updates the modification time and size after the file has been written
+func (fp *fingerprint) stat(info os.FileInfo) {
+	fp.modTime = info.ModTime()
+	fp.size = info.Size()
+}
+
+// changed reports whether the file differs from the fingerprint. A file touched without
+// changing its contents is not reported. A file that no longer exists has nothing to lose
+func (fp *fingerprint) changed() (bool, error) {
+
+	info, err := os.Stat(fp.path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if info.Size() != fp.size {
+		return true, nil
+	}
+
+	if info.ModTime().Equal(fp.modTime) {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(fp.path)
+	if err != nil {
+		return false, err
+	}
+
+	sum := sha256.Sum256(data)
+	if !bytes.Equal(sum[:], fp.hash.Sum(nil)) {
+		return true, nil
+	}
+
+	fp.modTime = info.ModTime()
+
+	return false, nil
+}
+
+// CheckFile returns ErrFileChanged if the file at filePath was changed since the DataFrame
+// loaded or saved it. Files the DataFrame has not loaded or saved are not checked
+func (df *DataFrame) CheckFile(filePath string) error {
+
+	filePath, err := getTrueFilepath(filePath)
+
+	if err != nil {
+		return err
+	}
+
+	if df.fingerprint == nil || df.fingerprint.path != filePath {
+		return nil
+	}
+
+	changed, err := df.fingerprint.changed()
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		return ErrFileChanged
+	}
+
+	return nil
+}
+
+// ResetFingerprint takes the current contents of the file at filePath as the loaded ones,
+// so that the next save overwrites the changes made outside of the DataFrame
+func (df *DataFrame) ResetFingerprint(filePath string) error {
+
+	filePath, err := getTrueFilepath(filePath)
+
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		df.fingerprint = nil
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, data, err := readFile(file)
+	if err != nil {
+		return err
+	}
+
+	df.fingerprint = newFingerprint(filePath, info, data)
+
+	return nil
+}
+
+// readFile reads an open file together with its FileInfo
+func readFile(file *os.File) (os.FileInfo, []byte, error) {
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data := make([]byte, info.Size())
+	if _, err := file.ReadAt(data, 0); err != nil && info.Size() > 0 {
+		return nil, nil, err
+	}
+
+	return info, data, nil
+}
+
+// MergeCSV loads the file at filePath again and repeats on it the changes made to the
+// DataFrame since it was last loaded or saved: added rows are added unless the file
+// already has them, deleted and updated rows are changed if the file still has them.
+// The undo history is cleared, as it no longer matches the rows.
+// Cannot be used in a transaction
+func (df *DataFrame) MergeCSV(filePath string) error {
+
+	if df.tx != nil {
+		return errors.New("cannot merge in a transaction")
+	}
+
+	columns := df.Columns
+	pending := df.pending
+
+	if err := df.LoadCSV(filePath); err != nil {
+		return err
+	}
+
+	if !slices.Equal(columns, df.Columns) {
+		return errors.New("the columns of the file were changed, the changes cannot be merged")
+	}
+
+	journal := df.journal
+	df.journal = nil
+
+	err := df.replay(pending)
+	df.journal = journal
+
+	if err != nil {
+		return err
+	}
+
+	if journal != nil {
+		return journal.Clear()
+	}
+
+	return nil
+}
+
+// replay repeats operations by the contents of the rows rather than by their indexes.
+// A row deleted or changed in the file is left as the file has it, and a sort is not
+// repeated, as its order refers to the rows before the file was changed
+func (df *DataFrame) replay(ops []Operation) error {
+
+	for _, op := range ops {
+
+		idx := slices.IndexFunc(df.Data, func(row []string) bool {
+			return op.Old != nil && slices.Equal(row, op.Old)
+		})
+
+		var err error
+
+		switch {
+		case op.Kind == OpAdd:
+			err = df.AddUniqueRow(op.Row)
+		case op.Kind == OpDelete && idx != -1:
+			err = df.DeleteRow(idx)
+		case op.Kind == OpUpdate && idx != -1:
+			err = df.UpdateRow(idx, op.Row)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
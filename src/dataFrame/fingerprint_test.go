@@ -0,0 +1,244 @@
+package dataFrame
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// writeDeck writes data to the deck file and moves its modification time forward,
+// so the change is seen even on file systems with a coarse timestamp resolution
+func writeDeck(t *testing.T, file, data string) {
+
+	if err := os.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(file, later, later); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSaveCSV_fileChanged(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "deck.csv")
+	writeDeck(t, file, "Word;Translation\ncat;кошка\n")
+
+	df := NewDataFrame(';')
+	if err := df.LoadCSV(file); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := df.AddRowAndSave([]string{"dog", "собака"}, file); err != nil {
+		t.Fatalf("Save of an unchanged file failed: %v", err)
+	}
+
+	writeDeck(t, file, "Word;Translation\ncat;кошка\ndog;собака\nfox;лиса\n")
+
+	if err := df.AddRowAndSave([]string{"owl", "сова"}, file); !errors.Is(err, ErrFileChanged) {
+		t.Fatalf("Expected ErrFileChanged, got %v", err)
+	}
+
+	data, _ := os.ReadFile(file)
+	if string(data) != "Word;Translation\ncat;кошка\ndog;собака\nfox;лиса\n" {
+		t.Errorf("Changed file was overwritten: %q", data)
+	}
+
+	if err := df.ResetFingerprint(file); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := df.SaveCSV(file); err != nil {
+		t.Fatalf("Save after ResetFingerprint failed: %v", err)
+	}
+
+	data, _ = os.ReadFile(file)
+	if string(data) != "Word;Translation\ncat;кошка\ndog;собака\nowl;сова\n" {
+		t.Errorf("File was not overwritten: %q", data)
+	}
+}
+
+func TestDiscardChanges(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "deck.csv")
+	writeDeck(t, file, "Word;Translation\ncat;кошка\n")
+
+	df := NewDataFrame(';')
+	if err := df.LoadCSV(file); err != nil {
+		t.Fatal(err)
+	}
+	df.SetJournal(NewJournal())
+
+	if err := df.AddRowAndSave([]string{"dog", "собака"}, file); err != nil {
+		t.Fatal(err)
+	}
+
+	writeDeck(t, file, "Word;Translation\ncat;кошка\ndog;собака\nfox;лиса\n")
+
+	if err := df.AddRowAndSave([]string{"owl", "сова"}, file); !errors.Is(err, ErrFileChanged) {
+		t.Fatalf("Expected ErrFileChanged, got %v", err)
+	}
+
+	if err := df.DiscardChanges(file); err != nil {
+		t.Fatalf("DiscardChanges error: %v", err)
+	}
+
+	want := [][]string{{"cat", "кошка"}, {"dog", "собака"}, {"fox", "лиса"}}
+	if !reflect.DeepEqual(df.Data, want) {
+		t.Errorf("Expected the file to be loaded again, got %v", df.Data)
+	}
+
+	// Only the unsaved change is dropped from the history
+	if undo := df.Journal().Undo; len(undo) != 1 || undo[0].Ops[0].Row[0] != "dog" {
+		t.Fatalf("Expected the saved change to stay in the history, got %v", undo)
+	}
+}
+
+func TestSaveCSV_touchedFileNotChanged(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "deck.csv")
+	writeDeck(t, file, "Word;Translation\ncat;кошка\n")
+
+	df := NewDataFrame(';')
+	df.SetAppendMode(true)
+	if err := df.LoadCSV(file); err != nil {
+		t.Fatal(err)
+	}
+
+	// The fingerprint follows rows appended by the DataFrame itself
+	if err := df.AddRowAndSave([]string{"dog", "собака"}, file); err != nil {
+		t.Fatal(err)
+	}
+
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(file, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := df.AddRowAndSave([]string{"owl", "сова"}, file); err != nil {
+		t.Errorf("Touched file was reported as changed: %v", err)
+	}
+}
+
+func TestMergeCSV(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "deck.csv")
+	writeDeck(t, file, "Word;Translation\ncat;кошка\ndog;собака\nowl;сова\n")
+
+	df := NewDataFrame(';')
+	df.SetJournal(NewJournal())
+	if err := df.LoadCSV(file); err != nil {
+		t.Fatal(err)
+	}
+
+	writeDeck(t, file, "Word;Translation\ncat;кошка\ndog;пёс\nowl;сова\nfox;лиса\n")
+
+	df.AddRow([]string{"bee", "пчела"})
+	df.UpdateRow(0, []string{"cat", "кот"})
+	df.DeleteRow(2)
+	df.UpdateRow(1, []string{"dog", "собачка"})
+
+	if err := df.SaveCSV(file); !errors.Is(err, ErrFileChanged) {
+		t.Fatalf("Expected ErrFileChanged, got %v", err)
+	}
+
+	if err := df.MergeCSV(file); err != nil {
+		t.Fatalf("MergeCSV error: %v", err)
+	}
+
+	// A row changed in both places is kept as the file has it
+	expected := [][]string{
+		{"cat", "кот"},
+		{"dog", "пёс"},
+		{"fox", "лиса"},
+		{"bee", "пчела"},
+	}
+
+	if !reflect.DeepEqual(df.Data, expected) {
+		t.Errorf("Merged data mismatch:\n got %v\nwant %v", df.Data, expected)
+	}
+
+	if df.Journal().CanUndo() {
+		t.Errorf("Expected the undo history to be cleared")
+	}
+
+	if err := df.SaveCSV(file); err != nil {
+		t.Errorf("Save after MergeCSV failed: %v", err)
+	}
+}
+
+func TestMergeCSV_updatedRowDeleted(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "deck.csv")
+	writeDeck(t, file, "Word;Translation\ncat;кошка\ndog;собака\n")
+
+	df := NewDataFrame(';')
+	if err := df.LoadCSV(file); err != nil {
+		t.Fatal(err)
+	}
+
+	writeDeck(t, file, "Word;Translation\ncat;кошка\n")
+
+	df.UpdateRow(1, []string{"dog", "пёс"})
+
+	if err := df.MergeCSV(file); err != nil {
+		t.Fatalf("MergeCSV error: %v", err)
+	}
+
+	// The row deleted from the file is not added back by the update
+	if expected := [][]string{{"cat", "кошка"}}; !reflect.DeepEqual(df.Data, expected) {
+		t.Errorf("Merged data mismatch:\n got %v\nwant %v", df.Data, expected)
+	}
+}
+
+func TestMergeCSV_undoneChanges(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "deck.csv")
+	writeDeck(t, file, "Word;Translation\ncat;кошка\n")
+
+	df := NewDataFrame(';')
+	df.SetJournal(NewJournal())
+	if err := df.LoadCSV(file); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := df.AddRowAndSave([]string{"dog", "собака"}, file); err != nil {
+		t.Fatal(err)
+	}
+
+	writeDeck(t, file, "Word;Translation\ncat;кошка\ndog;собака\nfox;лиса\n")
+
+	if _, err := df.UndoAndSave(file); !errors.Is(err, ErrFileChanged) {
+		t.Fatalf("Expected ErrFileChanged, got %v", err)
+	}
+
+	if err := df.MergeCSV(file); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]string{{"cat", "кошка"}, {"fox", "лиса"}}
+	if !reflect.DeepEqual(df.Data, expected) {
+		t.Errorf("Expected the undone row to stay removed, got %v", df.Data)
+	}
+}
+
+func TestMergeCSV_columnsChanged(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "deck.csv")
+	writeDeck(t, file, "Word;Translation\ncat;кошка\n")
+
+	df := NewDataFrame(';')
+	if err := df.LoadCSV(file); err != nil {
+		t.Fatal(err)
+	}
+
+	writeDeck(t, file, "Word;Translation;Tags\ncat;кошка;animals\n")
+
+	if err := df.MergeCSV(file); err == nil {
+		t.Errorf("Expected an error for changed columns")
+	}
+}
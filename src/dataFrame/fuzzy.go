@@ -0,0 +1,134 @@
+package dataFrame
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// articles are the words removed from the start of values before near matches are
+// searched, so that "to run" matches "run" and "der Hund" matches "Hund"
+var articles = []string{
+	"to", "a", "an", "the",
+	"der", "die", "das", "ein", "eine",
+	"le", "la", "les", "un", "une",
+	"el", "los", "las", "una",
+}
+
+// lettersPerEdit is the number of letters of the shorter value allowed per edit,
+// so that short words, which differ in a letter from many others, match only
+// when they are equal without their articles
+const lettersPerEdit = 4
+
+// Match is a row whose value is close to a searched value
+type Match struct {
+	Index    int // index of the row
+	Distance int // edits between the searched value and the value of the row
+}
+
+// StripArticle removes a leading article, or "to" of an English verb, from s
+func StripArticle(s string) string {
+
+	s = strings.TrimSpace(s)
+
+	if first, rest, ok := strings.Cut(s, " "); ok && slices.Contains(articles, strings.ToLower(first)) {
+		return strings.TrimSpace(rest)
+	}
+
+	// French elision: l'arbre
+	if len(s) > 2 && (strings.HasPrefix(s, "l'") || strings.HasPrefix(s, "L'")) {
+		return s[2:]
+	}
+
+	return s
+}
+
+// EditDistance returns the number of letters inserted, deleted, replaced or swapped
+// with the next one that turn a into b (the optimal string alignment distance)
+func EditDistance(a, b string) int {
+
+	x, y := []rune(a), []rune(b)
+
+	// Three rows of the distance matrix: two rows back, the previous row and the current one
+	prev2 := make([]int, len(y)+1)
+	prev := make([]int, len(y)+1)
+	curr := make([]int, len(y)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(x); i++ {
+
+		curr[0] = i
+
+		for j := 1; j <= len(y); j++ {
+
+			cost := 1
+			if x[i-1] == y[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+
+			if i > 1 && j > 1 && x[i-1] == y[j-2] && x[i-2] == y[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return prev[len(y)]
+}
+
+// NearMatches returns up to limit rows whose value in column is at most maxDistance edits
+// away from value, closest first. Values are compared in the form given by the key
+// comparison (ignoring case if no key is set) without a leading article, and one edit
+// is allowed per lettersPerEdit letters of the shorter value
+func (df *DataFrame) NearMatches(column, value string, maxDistance, limit int) ([]Match, error) {
+
+	idx := df.columnIndex(column)
+	if idx == -1 {
+		return nil, errors.New("column name not found")
+	}
+
+	compare := df.comparison
+	if compare == nil {
+		compare = CompareFold
+	}
+
+	target := StripArticle(compare(value))
+	targetLen := utf8.RuneCountInString(target)
+
+	var matches []Match
+
+	for i, row := range df.Data {
+
+		if idx >= len(row) {
+			continue
+		}
+
+		form := StripArticle(compare(row[idx]))
+		formLen := utf8.RuneCountInString(form)
+
+		allowed := min(maxDistance, min(targetLen, formLen)/lettersPerEdit)
+
+		// The lengths alone need more edits than allowed
+		if form == "" || max(targetLen, formLen)-min(targetLen, formLen) > allowed {
+			continue
+		}
+
+		if distance := EditDistance(target, form); distance <= allowed {
+			matches = append(matches, Match{Index: i, Distance: distance})
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a, b Match) int {
+		return cmp.Compare(a.Distance, b.Distance)
+	})
+
+	return matches[:min(len(matches), limit)], nil
+}
@@ -0,0 +1,94 @@
+package dataFrame
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"colour", "color", 1},
+		{"recieve", "receive", 1},
+		{"kitten", "sitting", 3},
+		{"ёж", "еж", 1},
+		{"ca", "abc", 3},
+	}
+
+	for _, c := range cases {
+		if got := EditDistance(c.a, c.b); got != c.want {
+			t.Errorf("EditDistance(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestStripArticle(t *testing.T) {
+
+	cases := map[string]string{
+		"to run":    "run",
+		"The house": "house",
+		"der Hund":  "Hund",
+		"l'arbre":   "arbre",
+		"the":       "the",
+		"toad":      "toad",
+	}
+
+	for in, want := range cases {
+		if got := StripArticle(in); got != want {
+			t.Errorf("StripArticle(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestNearMatches(t *testing.T) {
+
+	df := NewDataFrame(';')
+	df.Columns = []string{"Word", "Translation"}
+	df.Data = [][]string{
+		{"colour", "цвет"},
+		{"receive", "получать"},
+		{"run", "бежать"},
+		{"car", "машина"},
+		{"colours", "цвета"},
+	}
+
+	cases := []struct {
+		value string
+		want  []Match
+	}{
+		{"color", []Match{{0, 1}}},
+		{"Colour", []Match{{0, 0}, {4, 1}}},
+		{"recieve", []Match{{1, 1}}},
+		{"to run", []Match{{2, 0}}},
+		{"cat", nil},
+	}
+
+	for _, c := range cases {
+
+		got, err := df.NearMatches("Word", c.value, 2, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("NearMatches(%q) = %v, want %v", c.value, got, c.want)
+		}
+	}
+
+	if got, _ := df.NearMatches("Word", "Colour", 2, 1); len(got) != 1 {
+		t.Errorf("Expected the matches to be limited, got %v", got)
+	}
+
+	if got, _ := df.NearMatches("Word", "color", 0, 5); len(got) != 0 {
+		t.Errorf("Expected no matches without edits allowed, got %v", got)
+	}
+
+	if _, err := df.NearMatches("Missing", "color", 2, 5); err == nil {
+		t.Error("Expected an error for a missing column")
+	}
+}
@@ -0,0 +1,109 @@
+package dataFrame
+
+import (
+	"errors"
+	"slices"
+)
+
+// rowIndex maps a row key to the indexes of the rows with that key in ascending order
+type rowIndex map[string][]int
+
+// EnableIndex builds an in-memory index on the key columns (see SetKey) and keeps it
+// up to date on LoadCSV, AddRow, UpdateRow and DeleteRow, so that key lookups take constant time.
+// If df.Data is changed directly, call EnableIndex again to rebuild the index
+func (df *DataFrame) EnableIndex() error {
+
+	if len(df.keyColumns) == 0 {
+		return errors.New("no key columns set")
+	}
+
+	df.indexed = true
+
+	return df.rebuildIndex()
+}
+
+// DisableIndex drops the index, key lookups fall back to a scan of df.Data
+func (df *DataFrame) DisableIndex() {
+	df.indexed = false
+	df.index = nil
+}
+
+// rebuildIndex builds the index from scratch if indexing is enabled
+func (df *DataFrame) rebuildIndex() error {
+
+	if !df.indexed {
+		return nil
+	}
+
+	df.index = nil
+
+	indexes, err := df.keyIndexes()
+	if err != nil {
+		return err
+	}
+
+	index := make(rowIndex, len(df.Data))
+
+	for i, row := range df.Data {
+		key := df.rowKey(row, indexes)
+		index[key] = append(index[key], i)
+	}
+
+	df.index = index
+
+	return nil
+}
+
+// indexAddedRow adds the last row of df.Data to the index
+func (df *DataFrame) indexAddedRow() {
+
+	if df.index == nil {
+		return
+	}
+
+	indexes, err := df.keyIndexes()
+	if err != nil {
+		df.index = nil
+		return
+	}
+
+	i := len(df.Data) - 1
+	key := df.rowKey(df.Data[i], indexes)
+	df.index[key] = append(df.index[key], i)
+}
+
+// FindRowsByKey returns the indexes of all rows with the given key values.
+// Values are given in the order of the key columns.
+func (df *DataFrame) FindRowsByKey(values ...string) ([]int, error) {
+
+	indexes, err := df.keyIndexes()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(values) != len(indexes) {
+		return nil, errors.New("values length does not match number of key columns")
+	}
+
+	// The index keeps its own slice, which must not be changed by the caller
+	return slices.Clone(df.findRows(df.makeKey(values), indexes)), nil
+}
+
+// findRows returns the indexes of the rows with the given key,
+// using the index when it is available
+func (df *DataFrame) findRows(key string, indexes []int) []int {
+
+	if df.index != nil {
+		return df.index[key]
+	}
+
+	var rows []int
+
+	for i, existing := range df.Data {
+		if df.rowKey(existing, indexes) == key {
+			rows = append(rows, i)
+		}
+	}
+
+	return rows
+}
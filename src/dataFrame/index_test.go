@@ -0,0 +1,94 @@
+package dataFrame
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEnableIndex_requiresKey(t *testing.T) {
+
+	df := NewDataFrame(';')
+	df.Columns = []string{"Word"}
+
+	if err := df.EnableIndex(); err == nil {
+		t.Error("Expected error when no key is set")
+	}
+}
+
+func TestIndex_keptCurrent(t *testing.T) {
+
+	df := NewDataFrame(';')
+	df.Columns = []string{"Word", "Translation"}
+	df.Data = [][]string{{"cat", "кошка"}, {"dog", "собака"}, {"Cat", "кот"}}
+
+	_ = df.SetKey(CompareFold, "Word")
+
+	if err := df.EnableIndex(); err != nil {
+		t.Fatalf("EnableIndex error: %v", err)
+	}
+
+	rows, err := df.FindRowsByKey("CAT")
+	if err != nil {
+		t.Fatalf("FindRowsByKey error: %v", err)
+	}
+
+	if !reflect.DeepEqual(rows, []int{0, 2}) {
+		t.Errorf("Expected rows [0 2], got %v", rows)
+	}
+
+	// Changing the result leaves the index as it is
+	rows[0] = 2
+	if rows, _ := df.FindRowsByKey("cat"); !reflect.DeepEqual(rows, []int{0, 2}) {
+		t.Errorf("Expected the index to be unchanged by the caller, got %v", rows)
+	}
+
+	_ = df.AddRow([]string{"bird", "птица"})
+
+	if rows, _ := df.FindRowsByKey("bird"); !reflect.DeepEqual(rows, []int{3}) {
+		t.Errorf("Expected added row to be indexed, got %v", rows)
+	}
+
+	_ = df.DeleteRow(0)
+
+	if rows, _ := df.FindRowsByKey("cat"); !reflect.DeepEqual(rows, []int{1}) {
+		t.Errorf("Expected index to shift after delete, got %v", rows)
+	}
+
+	if err := df.AddUniqueRow([]string{"DOG", "пёс"}); err != nil {
+		t.Fatalf("AddUniqueRow error: %v", err)
+	}
+
+	if len(df.Data) != 3 {
+		t.Errorf("Expected duplicate to be rejected through the index, got %v", df.Data)
+	}
+
+	df.DisableIndex()
+
+	if rows, _ := df.FindRowsByKey("bird"); !reflect.DeepEqual(rows, []int{2}) {
+		t.Errorf("Expected scan lookup without index, got %v", rows)
+	}
+}
+
+func TestIndex_builtOnLoad(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "deck.csv")
+
+	if err := os.WriteFile(file, []byte("Word;Translation\ncat;кошка\ndog;собака\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	df := NewDataFrame(';')
+	df.Columns = []string{"Word", "Translation"}
+	_ = df.SetKey(CompareExact, "Word")
+	_ = df.EnableIndex()
+
+	if err := df.LoadCSV(file); err != nil {
+		t.Fatalf("LoadCSV error: %v", err)
+	}
+
+	if rows, _ := df.FindRowsByKey("dog"); !reflect.DeepEqual(rows, []int{1}) {
+		t.Errorf("Expected index built on load, got %v", rows)
+	}
+}
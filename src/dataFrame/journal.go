@@ -0,0 +1,358 @@
+package dataFrame
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// maxJournalEntries is the number of changes a Journal keeps for undo
+const maxJournalEntries = 100
+
+// ErrJournalMismatch is returned when the recorded history does not match the data,
+// for example because the file was edited outside of DeckBuilder
+var ErrJournalMismatch = errors.New("history does not match the deck")
+
+// OpKind is the kind of a row operation recorded in a Journal
+type OpKind string
+
+const (
+	OpAdd    OpKind = "add"
+	OpDelete OpKind = "delete"
+	OpUpdate OpKind = "update"
+	OpSort   OpKind = "sort"
+)
+
+// Operation is a single row change: Row is the row after the change (add, update)
+// and Old is the row before it (delete, update). A sort reorders all rows instead:
+// row i of the result is row Order[i] before it
+type Operation struct {
+	Kind  OpKind   `json:"kind"`
+	Index int      `json:"index"`
+	Row   []string `json:"row,omitempty"`
+	Old   []string `json:"old,omitempty"`
+	Order []int    `json:"order,omitempty"`
+}
+
+// JournalEntry is a group of operations made by one call, undone and redone together
+type JournalEntry struct {
+	Ops []Operation `json:"ops"`
+}
+
+// Journal records the changes of a DataFrame so that they can be undone and redone.
+// A Journal opened with OpenJournal is saved to its file after every change
+type Journal struct {
+	Undo []JournalEntry `json:"undo"`
+	Redo []JournalEntry `json:"redo"`
+	path string
+}
+
+// NewJournal creates an empty Journal that is kept in memory only
+func NewJournal() *Journal {
+	return &Journal{}
+}
+
+// OpenJournal loads the Journal stored at filePath, or creates an empty one
+// if the file does not exist yet
+func OpenJournal(filePath string) (*Journal, error) {
+
+	filePath, err := getTrueFilepath(filePath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	j := &Journal{path: filePath}
+
+	data, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return j, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, j); err != nil {
+		return nil, err
+	}
+
+	return j, nil
+}
+
+// CanUndo reports whether there is a change to undo
+func (j *Journal) CanUndo() bool {
+	return len(j.Undo) > 0
+}
+
+// CanRedo reports whether there is an undone change to redo
+func (j *Journal) CanRedo() bool {
+	return len(j.Redo) > 0
+}
+
+// Clear forgets all recorded changes
+func (j *Journal) Clear() error {
+	j.Undo = nil
+	j.Redo = nil
+	return j.save()
+}
+
+// record adds a change to the undo history and drops the redo history
+func (j *Journal) record(ops ...Operation) error {
+
+	if len(ops) == 0 {
+		return nil
+	}
+
+	j.Undo = append(j.Undo, JournalEntry{Ops: ops})
+	j.Redo = nil
+
+	if len(j.Undo) > maxJournalEntries {
+		j.Undo = j.Undo[len(j.Undo)-maxJournalEntries:]
+	}
+
+	return j.save()
+}
+
+// save writes the Journal to its file, if it has one
+func (j *Journal) save() error {
+
+	if j.path == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(j.path), 0755); err != nil {
+		return err
+	}
+
+	return WriteFileAtomic(j.path, func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(j)
+	})
+}
+
+// SetJournal attaches a Journal that records every change made through AddRow,
+// AddUniqueRow, UpdateRow, UpdateCell, DeleteRow and DeleteRowsWhere. nil detaches it
+func (df *DataFrame) SetJournal(j *Journal) {
+	df.journal = j
+	df.markSaved()
+}
+
+// Journal returns the attached Journal, or nil
+func (df *DataFrame) Journal() *Journal {
+	return df.journal
+}
+
+// journalState is the undo and redo history of a Journal at some point
+type journalState struct {
+	undo []JournalEntry
+	redo []JournalEntry
+}
+
+// markSaved marks the rows and the history as the ones of the file
+func (df *DataFrame) markSaved() {
+
+	df.pending = nil
+	df.saved = journalState{}
+
+	if df.journal != nil {
+		df.saved = journalState{undo: slices.Clone(df.journal.Undo), redo: slices.Clone(df.journal.Redo)}
+	}
+}
+
+// DiscardChanges loads filePath again, dropping the changes made since the file was last
+// loaded or saved. The history goes back to that point too, so the changes made before
+// are kept and can still be undone. Cannot be used in a transaction
+func (df *DataFrame) DiscardChanges(filePath string) error {
+
+	if df.tx != nil {
+		return errors.New("cannot discard changes in a transaction")
+	}
+
+	if j := df.journal; j != nil {
+
+		j.Undo, j.Redo = df.saved.undo, df.saved.redo
+
+		if err := j.save(); err != nil {
+			return err
+		}
+	}
+
+	return df.LoadCSV(filePath)
+}
+
+// record adds a change to the attached Journal, if any.
+// In a transaction the operations are collected and recorded on Commit
+func (df *DataFrame) record(ops ...Operation) error {
+
+	if df.tx != nil {
+		df.tx.ops = append(df.tx.ops, ops...)
+		return nil
+	}
+
+	df.pending = append(df.pending, ops...)
+
+	if df.journal == nil {
+		return nil
+	}
+
+	return df.journal.record(ops...)
+}
+
+// Undo reverts the last recorded change. Returns false if there was nothing to undo
+func (df *DataFrame) Undo() (bool, error) {
+
+	if df.journal == nil || !df.journal.CanUndo() {
+		return false, nil
+	}
+
+	j := df.journal
+	entry := j.Undo[len(j.Undo)-1]
+
+	ops := make([]Operation, 0, len(entry.Ops))
+	for i := len(entry.Ops) - 1; i >= 0; i-- {
+		ops = append(ops, entry.Ops[i].inverse())
+	}
+
+	if err := df.applyAll(ops); err != nil {
+		return false, errors.Join(err, j.Clear())
+	}
+
+	j.Undo = j.Undo[:len(j.Undo)-1]
+	j.Redo = append(j.Redo, entry)
+
+	return true, errors.Join(df.rebuildIndex(), j.save())
+}
+
+// Redo applies the last undone change again. Returns false if there was nothing to redo
+func (df *DataFrame) Redo() (bool, error) {
+
+	if df.journal == nil || !df.journal.CanRedo() {
+		return false, nil
+	}
+
+	j := df.journal
+	entry := j.Redo[len(j.Redo)-1]
+
+	if err := df.applyAll(entry.Ops); err != nil {
+		return false, errors.Join(err, j.Clear())
+	}
+
+	j.Redo = j.Redo[:len(j.Redo)-1]
+	j.Undo = append(j.Undo, entry)
+
+	return true, errors.Join(df.rebuildIndex(), j.save())
+}
+
+// UndoAndSave reverts the last recorded change and saves the DataFrame to a CSV file
+func (df *DataFrame) UndoAndSave(filePath string) (bool, error) {
+
+	ok, err := df.Undo()
+	if !ok || err != nil {
+		return ok, err
+	}
+
+	return true, df.saveChanges(filePath)
+}
+
+// RedoAndSave applies the last undone change again and saves the DataFrame to a CSV file
+func (df *DataFrame) RedoAndSave(filePath string) (bool, error) {
+
+	ok, err := df.Redo()
+	if !ok || err != nil {
+		return ok, err
+	}
+
+	return true, df.saveChanges(filePath)
+}
+
+// inverse returns the operation that reverts op
+func (op Operation) inverse() Operation {
+
+	switch op.Kind {
+	case OpAdd:
+		return Operation{Kind: OpDelete, Index: op.Index, Old: op.Row}
+	case OpDelete:
+		return Operation{Kind: OpAdd, Index: op.Index, Row: op.Old}
+	case OpUpdate:
+		return Operation{Kind: OpUpdate, Index: op.Index, Row: op.Old, Old: op.Row}
+	case OpSort:
+		order := make([]int, len(op.Order))
+		for i, idx := range op.Order {
+			if idx >= 0 && idx < len(order) {
+				order[idx] = i
+			}
+		}
+		return Operation{Kind: OpSort, Order: order}
+	}
+
+	return op
+}
+
+// applyAll performs operations in order without recording them in the journal.
+// If one of them does not match the rows, none of them is applied
+func (df *DataFrame) applyAll(ops []Operation) error {
+
+	data := slices.Clone(df.Data)
+
+	for _, op := range ops {
+		if err := df.apply(op); err != nil {
+			df.Data = data
+			return err
+		}
+	}
+
+	df.pending = append(df.pending, ops...)
+
+	return nil
+}
+
+// apply performs an operation without recording it.
+// The rows it touches must look the way the operation expects
+func (df *DataFrame) apply(op Operation) error {
+
+	switch op.Kind {
+	case OpAdd:
+		if op.Index < 0 || op.Index > len(df.Data) {
+			return ErrJournalMismatch
+		}
+		df.Data = slices.Insert(df.Data, op.Index, slices.Clone(op.Row))
+	case OpDelete, OpUpdate:
+		if op.Index < 0 || op.Index >= len(df.Data) || !slices.Equal(df.Data[op.Index], op.Old) {
+			return ErrJournalMismatch
+		}
+		if op.Kind == OpDelete {
+			df.Data = slices.Delete(df.Data, op.Index, op.Index+1)
+		} else {
+			df.Data[op.Index] = slices.Clone(op.Row)
+		}
+	case OpSort:
+		if !isPermutation(op.Order, len(df.Data)) {
+			return ErrJournalMismatch
+		}
+		df.Data = df.rowsAt(op.Order)
+	default:
+		return ErrJournalMismatch
+	}
+
+	return nil
+}
+
+// isPermutation reports whether order has every index of n rows exactly once
+func isPermutation(order []int, n int) bool {
+
+	if len(order) != n {
+		return false
+	}
+
+	seen := make([]bool, n)
+	for _, idx := range order {
+		if idx < 0 || idx >= n || seen[idx] {
+			return false
+		}
+		seen[idx] = true
+	}
+
+	return true
+}
@@ -0,0 +1,169 @@
+package dataFrame
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func newJournalFrame() *DataFrame {
+
+	df := NewDataFrame(';')
+	df.Columns = []string{"Word", "Translation"}
+	df.Data = [][]string{{"cat", "кошка"}, {"dog", "собака"}, {"cat", "кот"}}
+	df.SetJournal(NewJournal())
+
+	return df
+}
+
+func TestJournal_undoRedo(t *testing.T) {
+
+	df := newJournalFrame()
+	original := [][]string{{"cat", "кошка"}, {"dog", "собака"}, {"cat", "кот"}}
+
+	_ = df.AddRow([]string{"bird", "птица"})
+	_ = df.UpdateCell(1, "Translation", "пёс")
+	_, _ = df.DeleteRowsWhere("Word", Equals("cat"))
+	_ = df.DeleteRow(0)
+
+	changed := [][]string{{"bird", "птица"}}
+	if !reflect.DeepEqual(df.Data, changed) {
+		t.Fatalf("Unexpected data after changes: %v", df.Data)
+	}
+
+	for i := 0; i < 4; i++ {
+		if ok, err := df.Undo(); !ok || err != nil {
+			t.Fatalf("Undo %d: %v, %v", i, ok, err)
+		}
+	}
+
+	if !reflect.DeepEqual(df.Data, original) {
+		t.Errorf("Undo did not restore data: %v", df.Data)
+	}
+
+	if ok, _ := df.Undo(); ok {
+		t.Error("Expected nothing left to undo")
+	}
+
+	for i := 0; i < 4; i++ {
+		if ok, err := df.Redo(); !ok || err != nil {
+			t.Fatalf("Redo %d: %v, %v", i, ok, err)
+		}
+	}
+
+	if !reflect.DeepEqual(df.Data, changed) {
+		t.Errorf("Redo did not repeat changes: %v", df.Data)
+	}
+
+	// A new change drops the redo history
+	_, _ = df.Undo()
+	_ = df.AddRow([]string{"fish", "рыба"})
+
+	if df.Journal().CanRedo() {
+		t.Error("Expected redo history to be dropped")
+	}
+}
+
+func TestJournal_persisted(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "history", "deck.json")
+
+	j, err := OpenJournal(path)
+	if err != nil {
+		t.Fatalf("OpenJournal error: %v", err)
+	}
+
+	df := newJournalFrame()
+	df.SetJournal(j)
+	_ = df.AddRow([]string{"bird", "птица"})
+
+	j2, err := OpenJournal(path)
+	if err != nil {
+		t.Fatalf("OpenJournal error: %v", err)
+	}
+
+	df.SetJournal(j2)
+
+	if ok, err := df.Undo(); !ok || err != nil {
+		t.Fatalf("Undo from persisted journal: %v, %v", ok, err)
+	}
+
+	if len(df.Data) != 3 {
+		t.Errorf("Expected added row to be undone, got %v", df.Data)
+	}
+}
+
+func TestJournal_mismatch(t *testing.T) {
+
+	df := newJournalFrame()
+	_ = df.AddRow([]string{"bird", "птица"})
+
+	// The row was changed behind the journal's back
+	df.Data[3] = []string{"fish", "рыба"}
+
+	if _, err := df.Undo(); !errors.Is(err, ErrJournalMismatch) {
+		t.Errorf("Expected ErrJournalMismatch, got %v", err)
+	}
+
+	if df.Journal().CanUndo() {
+		t.Error("Expected journal to be cleared after a mismatch")
+	}
+}
+
+func TestJournal_mismatchKeepsData(t *testing.T) {
+
+	df := newJournalFrame()
+	df.Data = [][]string{{"lion", "лев"}, {"wolf", "волк"}, {"cat", "кот"}}
+	_ = df.journal.record(Operation{Kind: OpUpdate, Index: 0, Row: []string{"lion", "лев"}, Old: []string{"cat", "кошка"}},
+		Operation{Kind: OpUpdate, Index: 1, Row: []string{"wolf", "волк"}, Old: []string{"dog", "собака"}})
+
+	// The first row of the entry was changed behind the journal's back, so the
+	// second operation undone does not match while the first one did
+	df.Data[0] = []string{"fish", "рыба"}
+	want := [][]string{{"fish", "рыба"}, {"wolf", "волк"}, {"cat", "кот"}}
+
+	if _, err := df.Undo(); !errors.Is(err, ErrJournalMismatch) {
+		t.Fatalf("Expected ErrJournalMismatch, got %v", err)
+	}
+
+	if !reflect.DeepEqual(df.Data, want) {
+		t.Errorf("Expected rows to stay unchanged, got %v", df.Data)
+	}
+
+	if len(df.pending) != 0 {
+		t.Errorf("Expected no pending changes after a failed undo, got %v", df.pending)
+	}
+}
+
+func TestUndoAndSave(t *testing.T) {
+
+	file := writeTestFile(t, "Word;Translation\ncat;кошка\n")
+
+	df := NewDataFrame(';')
+	_ = df.LoadCSV(file)
+	df.SetJournal(NewJournal())
+
+	_ = df.AddRowAndSave([]string{"dog", "собака"}, file)
+
+	if ok, err := df.UndoAndSave(file); !ok || err != nil {
+		t.Fatalf("UndoAndSave: %v, %v", ok, err)
+	}
+
+	df2 := NewDataFrame(';')
+	_ = df2.LoadCSV(file)
+
+	if len(df2.Data) != 1 {
+		t.Errorf("Expected undo to be saved, got %v", df2.Data)
+	}
+
+	if ok, err := df.RedoAndSave(file); !ok || err != nil {
+		t.Fatalf("RedoAndSave: %v, %v", ok, err)
+	}
+
+	_ = df2.LoadCSV(file)
+
+	if len(df2.Data) != 2 {
+		t.Errorf("Expected redo to be saved, got %v", df2.Data)
+	}
+}
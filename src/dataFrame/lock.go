@@ -0,0 +1,49 @@
+package dataFrame
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// ErrLocked is returned by LockFile when another process holds the lock
+var ErrLocked = errors.New("the file is locked by another process")
+
+// FileLock is an advisory lock held on a lock file. It only keeps out other processes
+// that take the same lock, such as another DeckBuilder writing the same deck
+type FileLock struct {
+	file *os.File
+}
+
+// LockFile takes the lock on the lock file at filePath, creating the file if needed.
+// Returns ErrLocked without waiting if another process holds it.
+// A lock file is used because SaveCSV replaces the deck with a new file
+func LockFile(filePath string) (*FileLock, error) {
+
+	filePath, err := getTrueFilepath(filePath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE, defaultFileMode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lockFile(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &FileLock{file: file}, nil
+}
+
+// Unlock releases the lock. The lock file is left in place
+func (l *FileLock) Unlock() error {
+	return errors.Join(unlockFile(l.file), l.file.Close())
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package dataFrame
+
+import "os"
+
+// lockFile does nothing: advisory locks are supported on Unix systems only
+func lockFile(file *os.File) error {
+	return nil
+}
+
+// unlockFile does nothing: advisory locks are supported on Unix systems only
+func unlockFile(file *os.File) error {
+	return nil
+}
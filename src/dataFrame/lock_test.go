@@ -0,0 +1,34 @@
+//go:build unix
+
+package dataFrame
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestLockFile(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "locks", "deck.lock")
+
+	lock, err := LockFile(file)
+	if err != nil {
+		t.Fatalf("LockFile error: %v", err)
+	}
+
+	if _, err := LockFile(file); !errors.Is(err, ErrLocked) {
+		t.Errorf("Expected ErrLocked for a held lock, got %v", err)
+	}
+
+	if err := lock.Unlock(); err != nil {
+		t.Fatalf("Unlock error: %v", err)
+	}
+
+	lock, err = LockFile(file)
+	if err != nil {
+		t.Fatalf("Expected the released lock to be taken, got %v", err)
+	}
+
+	lock.Unlock()
+}
@@ -0,0 +1,117 @@
+package dataFrame
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NFC returns s in Unicode Normalization Form C, so that a composed "é" and an "e"
+// followed by a combining accent are the same text
+func NFC(s string) string {
+	return norm.NFC.String(s)
+}
+
+// Normalizer is a step of the normalization of the values entered into a deck
+type Normalizer struct {
+	Name        string // name used in the deck settings
+	Description string
+	Apply       func(value string) string
+}
+
+// Normalizers lists the available steps in the order they are applied
+var Normalizers = []Normalizer{
+	{Name: "nfc", Description: "Unicode NFC: compose letters with accents", Apply: NFC},
+	{Name: "quotes", Description: "replace typographic quotes with \" and '", Apply: ReplaceQuotes},
+	{Name: "spaces", Description: "collapse whitespace", Apply: CollapseSpaces},
+	{Name: "lower", Description: "convert to lower case", Apply: strings.ToLower},
+	{Name: "punct", Description: "strip trailing punctuation", Apply: StripTrailingPunctuation},
+}
+
+// quoteReplacer replaces typographic quotes with ASCII ones
+var quoteReplacer = strings.NewReplacer(
+	"\u201C", `"`, "\u201D", `"`, "\u201E", `"`, "\u201F", `"`, "\u00AB", `"`, "\u00BB", `"`,
+	"\u2018", "'", "\u2019", "'", "\u201A", "'", "\u201B", "'", "\u2039", "'", "\u203A", "'",
+)
+
+// ReplaceQuotes replaces typographic quotes, such as “”, «» and ‘’, with " and '
+func ReplaceQuotes(s string) string {
+	return quoteReplacer.Replace(s)
+}
+
+// CollapseSpaces replaces runs of whitespace with a single space and removes
+// the whitespace around s
+func CollapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// StripTrailingPunctuation removes the sentence punctuation (.,;:!?…) and the
+// whitespace at the end of s
+func StripTrailingPunctuation(s string) string {
+	return strings.TrimRightFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(".,;:!?…", r)
+	})
+}
+
+// Pipeline is a sequence of normalization steps
+type Pipeline []Normalizer
+
+// ParsePipeline parses the names of steps separated by commas or spaces, "none" for no
+// steps. The steps are applied in the order of Normalizers, whatever the order of names
+func ParsePipeline(names string) (Pipeline, error) {
+
+	var pipeline Pipeline
+	fields := strings.FieldsFunc(strings.ToLower(names), func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+
+	for _, name := range fields {
+		if name != "none" && !slices.ContainsFunc(Normalizers, func(n Normalizer) bool { return n.Name == name }) {
+			return nil, errors.New("unknown normalization '" + name + "'")
+		}
+	}
+
+	for _, n := range Normalizers {
+		if slices.Contains(fields, n.Name) {
+			pipeline = append(pipeline, n)
+		}
+	}
+
+	return pipeline, nil
+}
+
+// String returns the names of the steps separated by commas, or "none"
+func (p Pipeline) String() string {
+
+	if len(p) == 0 {
+		return "none"
+	}
+
+	names := make([]string, len(p))
+	for i, n := range p {
+		names[i] = n.Name
+	}
+
+	return strings.Join(names, ",")
+}
+
+// Apply returns value normalized by every step, without surrounding whitespace
+func (p Pipeline) Apply(value string) string {
+
+	for _, n := range p {
+		value = n.Apply(value)
+	}
+
+	return strings.TrimSpace(value)
+}
+
+// Comparison returns a Comparison of the normalized values that ignores case,
+// so that entries are duplicates when they differ only in what the pipeline changes
+func (p Pipeline) Comparison() Comparison {
+	return func(value string) string {
+		return strings.ToLower(p.Apply(value))
+	}
+}
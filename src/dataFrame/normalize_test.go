@@ -0,0 +1,75 @@
+package dataFrame
+
+import "testing"
+
+func TestNFC(t *testing.T) {
+
+	cases := map[string]string{
+		"café": "café",
+		"Ёж":   "Ёж",
+		"й":    "й",
+		"plain": "plain",
+		"x́":    "x́",
+		"́a":    "́a",
+		// Stacked and reordered marks and Hangul jamo
+		"u\u0308\u0301": "\u01d8",
+		"a\u0302\u0323": "\u1ead",
+		"\u1100\u1161":  "\uac00",
+	}
+
+	for in, want := range cases {
+		if got := NFC(in); got != want {
+			t.Errorf("NFC(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParsePipeline(t *testing.T) {
+
+	cases := map[string]string{
+		"":                   "none",
+		"none":               "none",
+		"lower, nfc":         "nfc,lower",
+		"punct spaces nfc":   "nfc,spaces,punct",
+		"quotes,LOWER,punct": "quotes,lower,punct",
+	}
+
+	for in, want := range cases {
+		pipeline, err := ParsePipeline(in)
+		if err != nil || pipeline.String() != want {
+			t.Errorf("ParsePipeline(%q) = %v, %v, want %s", in, pipeline, err, want)
+		}
+	}
+
+	if _, err := ParsePipeline("nfc,upper"); err == nil {
+		t.Error("Expected an error for an unknown step")
+	}
+}
+
+func TestPipeline_Apply(t *testing.T) {
+
+	all, _ := ParsePipeline("nfc,quotes,spaces,lower,punct")
+	basic, _ := ParsePipeline("nfc,spaces")
+
+	cases := []struct {
+		pipeline Pipeline
+		in, want string
+	}{
+		{basic, "  hello \t world  ", "hello world"},
+		{basic, "Café!", "Café!"},
+		{all, " «Hello»,  “World”… ", `"hello", "world"`},
+		{all, "it’s ok?!", "it's ok"},
+		{nil, "  As  is ", "As  is"},
+	}
+
+	for _, c := range cases {
+		if got := c.pipeline.Apply(c.in); got != c.want {
+			t.Errorf("%s.Apply(%q) = %q, want %q", c.pipeline, c.in, got, c.want)
+		}
+	}
+
+	compare := basic.Comparison()
+	if compare("  Café ") != compare("café") {
+		t.Error("Expected values that differ in case and composition to compare equal")
+	}
+}
@@ -0,0 +1,216 @@
+package dataFrame
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Predicate reports whether a cell value matches a condition
+type Predicate func(value string) bool
+
+// Equals matches values equal to value
+func Equals(value string) Predicate {
+	return func(v string) bool {
+		return v == value
+	}
+}
+
+// Contains matches values containing substr, ignoring case
+func Contains(substr string) Predicate {
+	substr = strings.ToLower(substr)
+	return func(v string) bool {
+		return strings.Contains(strings.ToLower(v), substr)
+	}
+}
+
+// HasPrefix matches values starting with prefix, ignoring case
+func HasPrefix(prefix string) Predicate {
+	prefix = strings.ToLower(prefix)
+	return func(v string) bool {
+		return strings.HasPrefix(strings.ToLower(v), prefix)
+	}
+}
+
+// Matches matches values matching the regular expression re
+func Matches(re *regexp.Regexp) Predicate {
+	return re.MatchString
+}
+
+// IsEmpty matches empty values and values of spaces only
+func IsEmpty() Predicate {
+	return func(v string) bool {
+		return strings.TrimSpace(v) == ""
+	}
+}
+
+// Not matches values that p does not match
+func Not(p Predicate) Predicate {
+	return func(v string) bool {
+		return !p(v)
+	}
+}
+
+// SortKey describes a column to sort by
+type SortKey struct {
+	Column     string                 // column name
+	Descending bool                   // sort from the largest value to the smallest
+	Less       func(a, b string) bool // ordering of values, byte order if nil
+}
+
+// derive creates a DataFrame with the same format as df holding the given columns and rows.
+// The rows are shared with df, not copied
+func (df *DataFrame) derive(columns []string, rows [][]string) *DataFrame {
+
+	return &DataFrame{
+		Columns:      columns,
+		Data:         rows,
+		delimiter:    df.delimiter,
+		dialect:      df.dialect,
+		encoding:     df.encoding,
+		saveEncoding: df.saveEncoding,
+	}
+}
+
+// rowsAt returns the rows at the given indexes
+func (df *DataFrame) rowsAt(indexes []int) [][]string {
+
+	rows := make([][]string, len(indexes))
+
+	for i, idx := range indexes {
+		rows[i] = df.Data[idx]
+	}
+
+	return rows
+}
+
+// cell returns the value at column idx of row, or "" for a short row
+func cell(row []string, idx int) string {
+
+	if idx < len(row) {
+		return row[idx]
+	}
+
+	return ""
+}
+
+// FindWhere returns the indexes of the rows whose value in column satisfies match
+func (df *DataFrame) FindWhere(column string, match Predicate) ([]int, error) {
+
+	colIdx := df.columnIndex(column)
+
+	if colIdx == -1 {
+		return nil, errors.New("column name not found")
+	}
+
+	var indexes []int
+
+	for i, row := range df.Data {
+		if match(cell(row, colIdx)) {
+			indexes = append(indexes, i)
+		}
+	}
+
+	return indexes, nil
+}
+
+// Find returns the indexes of the rows whose value in column equals value
+func (df *DataFrame) Find(column, value string) ([]int, error) {
+	return df.FindWhere(column, Equals(value))
+}
+
+// Filter returns a new DataFrame with the rows whose value in column satisfies match.
+// The rows are shared with df
+func (df *DataFrame) Filter(column string, match Predicate) (*DataFrame, error) {
+
+	indexes, err := df.FindWhere(column, match)
+	if err != nil {
+		return nil, err
+	}
+
+	return df.derive(df.Columns, df.rowsAt(indexes)), nil
+}
+
+// SortIndexes returns the indexes of the rows in the order given by keys.
+// The sort is stable: rows with equal keys keep their order
+func (df *DataFrame) SortIndexes(keys ...SortKey) ([]int, error) {
+
+	colIdx := make([]int, len(keys))
+
+	for i, key := range keys {
+		colIdx[i] = df.columnIndex(key.Column)
+		if colIdx[i] == -1 {
+			return nil, errors.New("column name not found")
+		}
+	}
+
+	indexes := make([]int, len(df.Data))
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	sort.SliceStable(indexes, func(i, j int) bool {
+
+		a, b := df.Data[indexes[i]], df.Data[indexes[j]]
+
+		for k, key := range keys {
+
+			x, y := cell(a, colIdx[k]), cell(b, colIdx[k])
+			if key.Descending {
+				x, y = y, x
+			}
+
+			less := key.Less
+			if less == nil {
+				less = func(a, b string) bool { return a < b }
+			}
+
+			if less(x, y) {
+				return true
+			}
+			if less(y, x) {
+				return false
+			}
+		}
+
+		return false
+	})
+
+	return indexes, nil
+}
+
+// Sort returns a new DataFrame with the rows sorted by keys. The rows are shared with df
+func (df *DataFrame) Sort(keys ...SortKey) (*DataFrame, error) {
+
+	indexes, err := df.SortIndexes(keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	return df.derive(df.Columns, df.rowsAt(indexes)), nil
+}
+
+// Select returns a new DataFrame with only the given columns in the given order
+func (df *DataFrame) Select(columns ...string) (*DataFrame, error) {
+
+	colIdx := make([]int, len(columns))
+
+	for i, name := range columns {
+		colIdx[i] = df.columnIndex(name)
+		if colIdx[i] == -1 {
+			return nil, errors.New("column name not found")
+		}
+	}
+
+	rows := make([][]string, len(df.Data))
+
+	for i, row := range df.Data {
+		rows[i] = make([]string, len(colIdx))
+		for j, idx := range colIdx {
+			rows[i][j] = cell(row, idx)
+		}
+	}
+
+	return df.derive(append([]string(nil), columns...), rows), nil
+}
@@ -0,0 +1,138 @@
+package dataFrame
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func newQueryFrame() *DataFrame {
+
+	df := NewDataFrame(';')
+	df.Columns = []string{"Word", "Translation"}
+	df.Data = [][]string{
+		{"cat", "кошка"},
+		{"Catalog", "каталог"},
+		{"dog", ""},
+		{"bird", "птица"},
+		{"cat", "кот"},
+	}
+
+	return df
+}
+
+func TestFindWhere(t *testing.T) {
+
+	df := newQueryFrame()
+
+	cases := []struct {
+		name  string
+		match Predicate
+		want  []int
+	}{
+		{"equals", Equals("cat"), []int{0, 4}},
+		{"contains", Contains("CAT"), []int{0, 1, 4}},
+		{"prefix", HasPrefix("ca"), []int{0, 1, 4}},
+		{"regex", Matches(regexp.MustCompile(`^[bd]`)), []int{2, 3}},
+		{"not", Not(HasPrefix("c")), []int{2, 3}},
+	}
+
+	for _, c := range cases {
+
+		got, err := df.FindWhere("Word", c.match)
+		if err != nil {
+			t.Fatalf("%s: FindWhere error: %v", c.name, err)
+		}
+
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+
+	if got, _ := df.FindWhere("Translation", IsEmpty()); !reflect.DeepEqual(got, []int{2}) {
+		t.Errorf("IsEmpty: got %v", got)
+	}
+
+	if _, err := df.Find("Missing", "cat"); err == nil {
+		t.Error("Expected error for unknown column")
+	}
+}
+
+func TestFilter(t *testing.T) {
+
+	df := newQueryFrame()
+
+	filtered, err := df.Filter("Translation", Not(IsEmpty()))
+	if err != nil {
+		t.Fatalf("Filter error: %v", err)
+	}
+
+	if len(filtered.Data) != 4 || filtered.Delimiter() != ';' {
+		t.Errorf("Unexpected filtered frame: %v", filtered.Data)
+	}
+
+	if len(df.Data) != 5 {
+		t.Error("Filter must not change the source frame")
+	}
+}
+
+func TestSort_stableMultiColumn(t *testing.T) {
+
+	df := newQueryFrame()
+
+	sorted, err := df.Sort(SortKey{Column: "Word"}, SortKey{Column: "Translation", Descending: true})
+	if err != nil {
+		t.Fatalf("Sort error: %v", err)
+	}
+
+	want := [][]string{
+		{"Catalog", "каталог"},
+		{"bird", "птица"},
+		{"cat", "кошка"},
+		{"cat", "кот"},
+		{"dog", ""},
+	}
+
+	if !reflect.DeepEqual(sorted.Data, want) {
+		t.Errorf("Sort mismatch: %v", sorted.Data)
+	}
+
+	// Equal keys keep their order
+	stable, _ := df.Sort(SortKey{Column: "Word", Less: func(a, b string) bool { return len(a) < len(b) }})
+
+	order := []string{"кошка", "", "кот", "птица", "каталог"}
+
+	for i, translation := range order {
+		if stable.Data[i][1] != translation {
+			t.Errorf("Stable sort mismatch: %v", stable.Data)
+			break
+		}
+	}
+
+	if _, err := df.Sort(SortKey{Column: "Missing"}); err == nil {
+		t.Error("Expected error for unknown column")
+	}
+}
+
+func TestSelect(t *testing.T) {
+
+	df := newQueryFrame()
+	df.Data[0] = []string{"short"}
+
+	selected, err := df.Select("Translation", "Word")
+	if err != nil {
+		t.Fatalf("Select error: %v", err)
+	}
+
+	if !reflect.DeepEqual(selected.Columns, []string{"Translation", "Word"}) {
+		t.Errorf("Columns mismatch: %v", selected.Columns)
+	}
+
+	if !reflect.DeepEqual(selected.Data[0], []string{"", "short"}) || selected.Data[1][0] != "каталог" {
+		t.Errorf("Data mismatch: %v", selected.Data)
+	}
+
+	if _, err := df.Select("Missing"); err == nil {
+		t.Error("Expected error for unknown column")
+	}
+}
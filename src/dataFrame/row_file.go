@@ -0,0 +1,219 @@
+package dataFrame
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// rowFileHead is the number of bytes OpenRowFile reads to detect the format of the file
+const rowFileHead = 64 * 1024
+
+// RowFile gives access to the rows of a CSV file without loading them into memory.
+// Only the offsets of the records are kept; rows are read from disk when requested
+type RowFile struct {
+	Columns []string // column names
+	source  io.ReaderAt
+	file    *os.File
+	offsets []int64 // start of every data record, followed by the end of the data
+	dialect Dialect
+}
+
+// OpenRowFile indexes the records of the CSV file at filePath. The delimiter, Anki
+// directives, BOM and encoding are handled as in LoadCSV. Files that are not in UTF-8
+// are decoded into memory, but their rows are still parsed only when requested
+func OpenRowFile(filePath string, delimiter rune) (*RowFile, error) {
+
+	filePath, err := getTrueFilepath(filePath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	rf, err := newRowFile(file, delimiter)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return rf, nil
+}
+
+// newRowFile detects the format of file and indexes its records
+func newRowFile(file *os.File, delimiter rune) (*RowFile, error) {
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	rf := &RowFile{source: file, file: file}
+	size := info.Size()
+
+	head := make([]byte, min(size, rowFileHead))
+	if _, err := file.ReadAt(head, 0); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if encoding := DetectEncoding(head); encoding != EncodingUTF8 {
+
+		data, err := io.ReadAll(io.NewSectionReader(file, 0, size))
+		if err != nil {
+			return nil, err
+		}
+
+		if data, err = decode(data, encoding); err != nil {
+			return nil, err
+		}
+
+		rf.source = bytes.NewReader(data)
+		head, size = data[:min(len(data), rowFileHead)], int64(len(data))
+	}
+
+	bom := bytes.HasPrefix(head, utf8BOM)
+	directives, rest, _ := splitDirectives(bytes.TrimPrefix(head, utf8BOM))
+	start := int64(len(head) - len(rest))
+
+	if i := findDirective(directives, DirectiveSeparator); i != -1 {
+		if r := parseSeparator(directives[i].Value); r != AutoDelimiter {
+			delimiter = r
+		}
+	}
+
+	rf.dialect = sniffDialect(rest, delimiter)
+	rf.dialect.BOM = bom
+
+	rf.offsets, err = indexRecords(io.NewSectionReader(rf.source, start, size-start), start)
+	if err != nil {
+		return nil, err
+	}
+
+	// With a #columns directive the file has no header row
+	if i := findDirective(directives, DirectiveColumns); i != -1 {
+		rf.Columns = splitFields(directives[i].Value, rf.dialect.Delimiter)
+		return rf, nil
+	}
+
+	if len(rf.offsets) < 2 {
+		return nil, errors.New("csv file is empty")
+	}
+
+	// The header row is indexed as the first record
+	header, err := rf.readRecords(0, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	rf.Columns = header[0]
+	rf.offsets = rf.offsets[1:]
+
+	return rf, nil
+}
+
+// indexRecords returns the offsets of the records read from r, starting at base,
+// followed by the end offset. Empty lines are skipped, as encoding/csv does
+func indexRecords(r io.Reader, base int64) ([]int64, error) {
+
+	reader := bufio.NewReaderSize(r, rowFileHead)
+	offsets := []int64{}
+	pos, start := base, base
+	inQuotes := false
+
+	for {
+		line, err := reader.ReadSlice('\n')
+		if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+			return nil, err
+		}
+
+		if bytes.Count(line, []byte{'"'})%2 == 1 {
+			inQuotes = !inQuotes
+		}
+
+		lineStart := pos
+		pos += int64(len(line))
+
+		if err != bufio.ErrBufferFull && !inQuotes {
+
+			blank := start == lineStart && len(bytes.TrimRight(line, "\r\n")) == 0
+			if !blank {
+				offsets = append(offsets, start)
+			}
+
+			start = pos
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	return append(offsets, pos), nil
+}
+
+// Len returns the number of data rows
+func (rf *RowFile) Len() int {
+	return len(rf.offsets) - 1
+}
+
+// Rows reads count rows starting at index start. The range is clipped to the rows
+// of the file. A record that cannot be parsed is returned as a single raw field
+func (rf *RowFile) Rows(start, count int) ([][]string, error) {
+
+	start = max(start, 0)
+	end := min(start+count, rf.Len())
+
+	if start >= end {
+		return nil, nil
+	}
+
+	return rf.readRecords(start, end)
+}
+
+// readRecords reads and parses the indexed records from start up to end
+func (rf *RowFile) readRecords(start, end int) ([][]string, error) {
+
+	first := rf.offsets[start]
+
+	data := make([]byte, rf.offsets[end]-first)
+	if _, err := rf.source.ReadAt(data, first); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, end-start)
+
+	for i := start; i < end; i++ {
+		rows = append(rows, rf.parseRecord(data[rf.offsets[i]-first:rf.offsets[i+1]-first]))
+	}
+
+	return rows, nil
+}
+
+// parseRecord parses a single record
+func (rf *RowFile) parseRecord(data []byte) []string {
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = rf.dialect.Delimiter
+	reader.LazyQuotes = rf.dialect.LazyQuotes
+	reader.FieldsPerRecord = -1
+
+	record, err := reader.Read()
+	if err != nil {
+		return []string{strings.TrimRight(string(data), "\r\n")}
+	}
+
+	return record
+}
+
+// Close closes the file
+func (rf *RowFile) Close() error {
+	return rf.file.Close()
+}
@@ -0,0 +1,101 @@
+package dataFrame
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOpenRowFile_matchesLoadCSV(t *testing.T) {
+
+	cp1251, _ := encode([]byte(deckText), EncodingWindows1251)
+
+	tests := map[string]string{
+		"plain":      "Word;Translation\ncat;кошка\ndog;собака\n",
+		"no newline": "Word;Translation\ncat;кошка\ndog;собака",
+		"quoted":     "Word,Translation\n\"a, b\",\"line 1\nline 2\"\n\n\"say \"\"hi\"\"\",x\r\n",
+		"BOM":        "\xEF\xBB\xBFWord\tTranslation\r\ncat\tкошка\r\n",
+		"directives": "#separator:Pipe\n#html:false\n#columns:Word|Translation\ncat|кошка\ndog|собака\n",
+		"empty deck": "Word;Translation\n",
+		"cp1251":     string(cp1251),
+	}
+
+	for name, contents := range tests {
+
+		file := writeTestFile(t, contents)
+
+		df := NewDataFrame(AutoDelimiter)
+		if err := df.LoadCSV(file); err != nil {
+			t.Fatalf("%s: LoadCSV error: %v", name, err)
+		}
+
+		rf, err := OpenRowFile(file, AutoDelimiter)
+		if err != nil {
+			t.Fatalf("%s: OpenRowFile error: %v", name, err)
+		}
+
+		rows, err := rf.Rows(0, rf.Len())
+		if err != nil {
+			t.Fatalf("%s: Rows error: %v", name, err)
+		}
+
+		if !reflect.DeepEqual(rf.Columns, df.Columns) || rf.Len() != len(df.Data) {
+			t.Errorf("%s: got %v with %d rows, expected %v with %d", name, rf.Columns, rf.Len(), df.Columns, len(df.Data))
+		}
+
+		if len(df.Data) > 0 && !reflect.DeepEqual(rows, df.Data) {
+			t.Errorf("%s: got %q, expected %q", name, rows, df.Data)
+		}
+
+		rf.Close()
+	}
+}
+
+func TestRowFile_Rows(t *testing.T) {
+
+	var contents strings.Builder
+	contents.WriteString("Word;Translation\n")
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&contents, "word%d;слово%d\n", i, i)
+	}
+
+	rf, err := OpenRowFile(writeTestFile(t, contents.String()), ';')
+	if err != nil {
+		t.Fatalf("OpenRowFile error: %v", err)
+	}
+	defer rf.Close()
+
+	if rf.Len() != 1000 {
+		t.Fatalf("Expected 1000 rows, got %d", rf.Len())
+	}
+
+	rows, _ := rf.Rows(500, 3)
+	expected := [][]string{{"word500", "слово500"}, {"word501", "слово501"}, {"word502", "слово502"}}
+
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Expected %v, got %v", expected, rows)
+	}
+
+	// The range is clipped to the file
+	if rows, _ := rf.Rows(998, 10); len(rows) != 2 {
+		t.Errorf("Expected 2 rows at the end, got %d", len(rows))
+	}
+
+	if rows, _ := rf.Rows(1000, 10); len(rows) != 0 {
+		t.Errorf("Expected no rows past the end, got %d", len(rows))
+	}
+}
+
+func TestOpenRowFile_errors(t *testing.T) {
+
+	if _, err := OpenRowFile(writeTestFile(t, ""), ';'); err == nil {
+		t.Error("Expected error for empty file")
+	}
+
+	if _, err := OpenRowFile(filepath.Join(t.TempDir(), "none.csv"), ';'); !os.IsNotExist(err) {
+		t.Errorf("Expected not exist error, got %v", err)
+	}
+}
@@ -0,0 +1,247 @@
+package dataFrame
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Alphabets in the order of their letters. A letter of an alphabet is a letter of its own,
+// so й follows и, while a letter missing from them, like ё or é, sorts as its base letter
+// with an accent: ёлка comes before ель, éclair right after eclair
+const (
+	latinAlphabet    = "abcdefghijklmnopqrstuvwxyz"
+	cyrillicAlphabet = "абвгґдђѓеєжзѕиіїйјклљмнњопрстћќуўфхцчџшщъыьэюя"
+)
+
+// Classes of characters in their order: punctuation and symbols, digits, Latin,
+// Greek and Cyrillic letters, then the letters of other scripts
+const (
+	classSymbol = iota + 1
+	classDigit
+	classLatin
+	classGreek
+	classCyrillic
+	classLetter
+)
+
+// alphabetSize is the number of positions kept for the letters of an alphabet,
+// the letters of its script missing from it following them by code point
+const alphabetSize = 64
+
+// Levels of a comparison, each deciding only when the previous ones found values equal
+const (
+	levelLetters = iota // letters, ignoring accents and case
+	levelAccents
+	levelCase
+)
+
+// decompose returns the base letter of r and the first combining mark of its canonical
+// decomposition, or r and 0 if r is not a letter with an accent
+func decompose(r rune) (rune, rune) {
+
+	var buf [utf8.UTFMax]byte
+
+	d := norm.NFD.Properties(utf8.AppendRune(buf[:0], r)).Decomposition()
+	if d == nil {
+		return r, 0
+	}
+
+	base, n := utf8.DecodeRune(d)
+	mark, _ := utf8.DecodeRune(d[n:])
+
+	if !unicode.Is(unicode.Mn, mark) {
+		return r, 0
+	}
+
+	return base, mark
+}
+
+// Collation orders values the way dictionaries do: digits before letters, Latin before
+// Cyrillic, letters in the order of their alphabet whatever their case and accents,
+// then unaccented before accented and lowercase before uppercase
+type Collation struct {
+	IgnoreArticles bool // sort "the house" as "house"
+}
+
+// Compare returns -1 if a sorts before b, 1 if after and 0 if they are equal
+func (c Collation) Compare(a, b string) int {
+
+	x, y := NFC(a), NFC(b)
+
+	if c.IgnoreArticles {
+		x, y = StripArticle(x), StripArticle(y)
+	}
+
+	for _, level := range []int{levelLetters, levelAccents, levelCase} {
+		if result := compareLevel(x, y, level); result != 0 {
+			return result
+		}
+	}
+
+	// Values that differ only in ignored characters still get a stable order
+	return cmp.Or(strings.Compare(x, y), strings.Compare(a, b))
+}
+
+// Less reports whether a sorts before b, for use as SortKey.Less
+func (c Collation) Less(a, b string) bool {
+	return c.Compare(a, b) < 0
+}
+
+// compareLevel compares the weights of the characters of a and b at level
+func compareLevel(a, b string, level int) int {
+
+	for {
+
+		x, okA := nextWeight(&a, level)
+		y, okB := nextWeight(&b, level)
+
+		if !okA || !okB {
+			return cmp.Compare(boolWeight(okA), boolWeight(okB))
+		}
+
+		if x != y {
+			return cmp.Compare(x, y)
+		}
+	}
+}
+
+// nextWeight returns the weight at level of the next character of s that has one,
+// and removes the characters read from s. Returns false at the end of s
+func nextWeight(s *string, level int) (uint32, bool) {
+
+	for *s != "" {
+
+		r, n := utf8.DecodeRuneInString(*s)
+		*s = (*s)[n:]
+
+		if weight := collationWeight(r, level); weight != 0 {
+			return weight, true
+		}
+	}
+
+	return 0, false
+}
+
+// collationWeight returns the weight of r at level, 0 if r is ignored at that level
+func collationWeight(r rune, level int) uint32 {
+
+	lower := unicode.ToLower(r)
+	base, mark := lower, rune(0)
+
+	if !inAlphabet(lower) {
+		base, mark = decompose(lower)
+	}
+
+	// A combining mark that was not composed accents the letter before it
+	if unicode.Is(unicode.Mn, r) {
+		if level == levelAccents {
+			return uint32(r)
+		}
+		return 0
+	}
+
+	switch level {
+	case levelAccents:
+		return uint32(mark) + 1
+	case levelCase:
+		if r != lower {
+			return 2
+		}
+		return 1
+	}
+
+	class, position := classSymbol, uint32(base)
+
+	switch {
+	case unicode.IsDigit(base):
+		class = classDigit
+	case unicode.Is(unicode.Latin, base):
+		class, position = classLatin, alphabetPosition(latinAlphabet, base)
+	case unicode.Is(unicode.Greek, base):
+		class = classGreek
+	case unicode.Is(unicode.Cyrillic, base):
+		class, position = classCyrillic, alphabetPosition(cyrillicAlphabet, base)
+	case unicode.IsLetter(base):
+		class = classLetter
+	}
+
+	return uint32(class)<<24 | position
+}
+
+// inAlphabet reports whether the lowercase letter r is a letter of its own in an alphabet
+func inAlphabet(r rune) bool {
+	return strings.ContainsRune(latinAlphabet, r) || strings.ContainsRune(cyrillicAlphabet, r)
+}
+
+// alphabetPosition returns the position of r in alphabet, or a position after
+// its letters if r is missing from it
+func alphabetPosition(alphabet string, r rune) uint32 {
+
+	position := 0
+	for _, letter := range alphabet {
+		position++
+		if letter == r {
+			return uint32(position)
+		}
+	}
+
+	return alphabetSize + uint32(r)
+}
+
+func boolWeight(b bool) int {
+
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+// SortRows reorders the rows of df by keys. The sort is recorded as a single operation
+// holding the new order of the rows, so it is undone at once. Returns the number
+// of rows moved
+func (df *DataFrame) SortRows(keys ...SortKey) (int, error) {
+
+	indexes, err := df.SortIndexes(keys...)
+	if err != nil {
+		return 0, err
+	}
+
+	rows := df.rowsAt(indexes)
+
+	moved := 0
+	for i, row := range rows {
+		if !slices.Equal(row, df.Data[i]) {
+			moved++
+		}
+	}
+
+	if moved == 0 {
+		return 0, nil
+	}
+
+	if err := df.record(Operation{Kind: OpSort, Order: indexes}); err != nil {
+		return 0, err
+	}
+
+	df.Data = rows
+
+	return moved, df.rebuildIndex()
+}
+
+// SortRowsAndSave reorders the rows of df by keys and saves the DataFrame to a CSV file.
+// The file is not written if the rows are already in order
+func (df *DataFrame) SortRowsAndSave(filePath string, keys ...SortKey) (int, error) {
+
+	moved, err := df.SortRows(keys...)
+	if err != nil || moved == 0 {
+		return moved, err
+	}
+
+	return moved, df.saveChanges(filePath)
+}
@@ -0,0 +1,128 @@
+package dataFrame
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestCollation_Compare(t *testing.T) {
+
+	want := []string{
+		"10", "2",
+		"apple", "Apple", "banana", "eclair", "éclair", "Éclair", "zebra",
+		"ёлка", "ель", "Ель", "жук", "играть", "йогурт", "яблоко",
+	}
+
+	values := slices.Clone(want)
+	slices.Reverse(values)
+	slices.SortFunc(values, Collation{}.Compare)
+
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("Sorted:\n%q\nwant:\n%q", values, want)
+	}
+
+	// A decomposed letter is the same letter as the composed one
+	if (Collation{}).Compare("cafe", "cafe\u0301") >= 0 || (Collation{}).Compare("cafe\u0301", "caf\u00e9s") >= 0 {
+		t.Error("Expected cafe < cafe\u0301 < caf\u00e9s")
+	}
+
+	if (Collation{}).Compare("caf\u00e9", "cafг") >= 0 {
+		t.Error("Expected an accented Latin letter before Cyrillic")
+	}
+}
+
+func TestCollation_ignoreArticles(t *testing.T) {
+
+	values := []string{"a zoo", "banana", "the apple", "to run"}
+
+	slices.SortFunc(values, Collation{}.Compare)
+	if want := []string{"a zoo", "banana", "the apple", "to run"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("Sorted with articles: %q", values)
+	}
+
+	slices.SortFunc(values, Collation{IgnoreArticles: true}.Compare)
+	if want := []string{"the apple", "banana", "to run", "a zoo"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("Sorted without articles: %q", values)
+	}
+}
+
+func TestSortRows(t *testing.T) {
+
+	df := newJournalFrame()
+	original := slices.Clone(df.Data)
+	_ = df.SetKey(CompareFold, "Word", "Translation")
+	_ = df.EnableIndex()
+
+	moved, err := df.SortRows(SortKey{Column: "Word", Descending: true, Less: Collation{}.Less})
+	if err != nil {
+		t.Fatalf("SortRows error: %v", err)
+	}
+
+	want := [][]string{{"dog", "собака"}, {"cat", "кошка"}, {"cat", "кот"}}
+	if moved != 2 || !reflect.DeepEqual(df.Data, want) {
+		t.Fatalf("Unexpected rows after sorting (%d moved): %v", moved, df.Data)
+	}
+
+	if rows, _ := df.FindRowsByKey("dog", "собака"); !reflect.DeepEqual(rows, []int{0}) {
+		t.Errorf("Expected index to follow the sort, got %v", rows)
+	}
+
+	if moved, _ := df.SortRows(SortKey{Column: "Word", Descending: true}); moved != 0 {
+		t.Errorf("Expected sorted rows to stay, %d moved", moved)
+	}
+
+	// The sort is recorded as one operation and undone at once
+	if ops := df.Journal().Undo[0].Ops; len(ops) != 1 || ops[0].Kind != OpSort {
+		t.Errorf("Expected a single sort operation, got %v", ops)
+	}
+
+	if ok, err := df.Undo(); !ok || err != nil {
+		t.Fatalf("Undo: %v, %v", ok, err)
+	}
+
+	if !reflect.DeepEqual(df.Data, original) {
+		t.Errorf("Undo did not restore the order: %v", df.Data)
+	}
+
+	if ok, err := df.Redo(); !ok || err != nil || !reflect.DeepEqual(df.Data, want) {
+		t.Fatalf("Redo: %v, %v, %v", ok, err, df.Data)
+	}
+
+	// A sort no longer matches the rows once one is added behind the journal's back
+	df.Data = append(df.Data, []string{"owl", "сова"})
+
+	if _, err := df.Undo(); !errors.Is(err, ErrJournalMismatch) {
+		t.Errorf("Expected ErrJournalMismatch, got %v", err)
+	}
+
+	if _, err := df.SortRows(SortKey{Column: "Missing"}); err == nil {
+		t.Error("Expected error for unknown column")
+	}
+}
+
+func TestSortRowsAndSave(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "deck.csv")
+
+	df := newJournalFrame()
+	if err := df.SaveCSV(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := df.SortRowsAndSave(path, SortKey{Column: "Translation", Less: Collation{}.Less}); err != nil {
+		t.Fatalf("SortRowsAndSave error: %v", err)
+	}
+
+	loaded := NewDataFrame(';')
+	if err := loaded.LoadCSV(path); err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{{"cat", "кот"}, {"cat", "кошка"}, {"dog", "собака"}}
+	if !reflect.DeepEqual(loaded.Data, want) {
+		t.Errorf("Unexpected saved rows: %v", loaded.Data)
+	}
+}
@@ -0,0 +1,151 @@
+package dataFrame
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// contextCheckRows is how often, in rows, LoadCSVContext and SaveCSVContext check for cancellation
+const contextCheckRows = 1024
+
+// SyncDataFrame is a DataFrame that can be used from several goroutines.
+// Reads take a read lock; changes, loading and saving take the write lock
+type SyncDataFrame struct {
+	mu sync.RWMutex
+	df *DataFrame
+}
+
+// NewSyncDataFrame creates an empty SyncDataFrame with a specified delimiter
+func NewSyncDataFrame(delimiter rune) *SyncDataFrame {
+	return &SyncDataFrame{df: NewDataFrame(delimiter)}
+}
+
+// Read calls f with the DataFrame under a read lock.
+// f must not change the DataFrame or keep references to it after returning
+func (s *SyncDataFrame) Read(f func(df *DataFrame) error) error {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return f(s.df)
+}
+
+// Write calls f with the DataFrame under the write lock.
+// f must not keep references to the DataFrame after returning
+func (s *SyncDataFrame) Write(f func(df *DataFrame) error) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return f(s.df)
+}
+
+// LoadCSVContext loads data from a CSV file, stopping when ctx is done
+func (s *SyncDataFrame) LoadCSVContext(ctx context.Context, filePath string) error {
+	return s.Write(func(df *DataFrame) error {
+		return df.LoadCSVContext(ctx, filePath)
+	})
+}
+
+// LoadCSVAsync loads data from a CSV file in a new goroutine and returns a channel
+// that receives the result. The write lock is taken before LoadCSVAsync returns,
+// so every later call waits for the load and sees the loaded data
+func (s *SyncDataFrame) LoadCSVAsync(ctx context.Context, filePath string) <-chan error {
+
+	done := make(chan error, 1)
+	s.mu.Lock()
+
+	go func() {
+		err := s.df.LoadCSVContext(ctx, filePath)
+		s.mu.Unlock()
+		done <- err
+	}()
+
+	return done
+}
+
+// SaveCSVContext saves the data to a CSV file, stopping when ctx is done
+func (s *SyncDataFrame) SaveCSVContext(ctx context.Context, filePath string) error {
+	return s.Write(func(df *DataFrame) error {
+		return df.SaveCSVContext(ctx, filePath)
+	})
+}
+
+// SetJournal attaches a Journal that records every change, nil detaches it
+func (s *SyncDataFrame) SetJournal(j *Journal) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.df.SetJournal(j)
+}
+
+// Columns returns a copy of the column names
+func (s *SyncDataFrame) Columns() []string {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Clone(s.df.Columns)
+}
+
+// Len returns the number of rows
+func (s *SyncDataFrame) Len() int {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.df.Data)
+}
+
+// Rows returns a copy of all rows
+func (s *SyncDataFrame) Rows() [][]string {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows := make([][]string, len(s.df.Data))
+	for i, row := range s.df.Data {
+		rows[i] = slices.Clone(row)
+	}
+
+	return rows
+}
+
+// Column returns a copy of the values of a column
+func (s *SyncDataFrame) Column(name string) ([]string, error) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.df.GetColumnByName(name)
+}
+
+// AddRowAndSave adds a row and saves the data to a CSV file
+func (s *SyncDataFrame) AddRowAndSave(row []string, filePath string) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.df.AddRowAndSave(slices.Clone(row), filePath)
+}
+
+// AddUniqueRowAndSave adds a row if it is not already present and saves the data to a CSV file
+func (s *SyncDataFrame) AddUniqueRowAndSave(row []string, filePath string) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.df.AddUniqueRowAndSave(slices.Clone(row), filePath)
+}
+
+// DeleteRowsWhereAndSave deletes every row whose value in column satisfies match
+// and saves the data to a CSV file. Returns the removed rows
+func (s *SyncDataFrame) DeleteRowsWhereAndSave(column string, match Predicate, filePath string) ([][]string, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.df.DeleteRowsWhereAndSave(column, match, filePath)
+}
@@ -0,0 +1,125 @@
+package dataFrame
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSyncDataFrame_concurrentReadersAndWriters(t *testing.T) {
+
+	file := writeTestFile(t, "Word;Translation\n")
+
+	s := NewSyncDataFrame(';')
+	if err := s.LoadCSVContext(context.Background(), file); err != nil {
+		t.Fatalf("LoadCSVContext error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				if err := s.AddRowAndSave([]string{fmt.Sprintf("w%d-%d", w, i), ""}, file); err != nil {
+					t.Errorf("AddRowAndSave error: %v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				rows := s.Rows()
+				words, _ := s.Column("Word")
+				_ = s.Len()
+				if len(words) < len(rows) {
+					t.Errorf("Column has %d values, rows %d", len(words), len(rows))
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if s.Len() != 100 {
+		t.Errorf("Expected 100 rows, got %d", s.Len())
+	}
+
+	df := NewDataFrame(';')
+	_ = df.LoadCSV(file)
+
+	if len(df.Data) != 100 {
+		t.Errorf("Expected 100 rows saved, got %d", len(df.Data))
+	}
+}
+
+func TestSyncDataFrame_LoadCSVAsync(t *testing.T) {
+
+	file := writeTestFile(t, "Word;Translation\ncat;кошка\n")
+
+	s := NewSyncDataFrame(';')
+	done := s.LoadCSVAsync(context.Background(), file)
+
+	// Calls made after LoadCSVAsync see the loaded data
+	if s.Len() != 1 {
+		t.Errorf("Expected the loaded row, got %d rows", s.Len())
+	}
+
+	if err := <-done; err != nil {
+		t.Errorf("LoadCSVAsync error: %v", err)
+	}
+}
+
+func TestLoadCSVContext_cancelled(t *testing.T) {
+
+	var contents strings.Builder
+	contents.WriteString("Word;Translation\n")
+	for i := 0; i < 3*contextCheckRows; i++ {
+		fmt.Fprintf(&contents, "word%d;\n", i)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	df := NewDataFrame(';')
+	df.Columns = []string{"Old"}
+
+	if err := df.LoadCSVContext(ctx, writeTestFile(t, contents.String())); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	if len(df.Columns) != 1 || len(df.Data) != 0 {
+		t.Errorf("DataFrame changed by a cancelled load: %v, %d rows", df.Columns, len(df.Data))
+	}
+}
+
+func TestSaveCSVContext_cancelled(t *testing.T) {
+
+	file := writeTestFile(t, "Word;Translation\ncat;кошка\n")
+
+	df := NewDataFrame(';')
+	_ = df.LoadCSV(file)
+	_ = df.AddRow([]string{"dog", "собака"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := df.SaveCSVContext(ctx, file); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	if data, _ := os.ReadFile(file); string(data) != "Word;Translation\ncat;кошка\n" {
+		t.Errorf("File changed by a cancelled save: %q", data)
+	}
+}
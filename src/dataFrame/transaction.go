@@ -0,0 +1,137 @@
+package dataFrame
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrInTransaction is returned by SaveCSV and AppendCSV while a transaction is open
+var ErrInTransaction = errors.New("the changes are staged in a transaction, use Commit to save them")
+
+// transaction holds the state of the DataFrame when Begin was called
+// and the operations made since then
+type transaction struct {
+	columns []string
+	data    [][]string
+	ops     []Operation
+}
+
+// Begin starts a transaction. Until Commit or Rollback, changes are staged in memory:
+// the *AndSave helpers do not write the file, SaveCSV and AppendCSV return ErrInTransaction,
+// and the changes are recorded in the journal as a single entry on Commit
+func (df *DataFrame) Begin() error {
+
+	if df.tx != nil {
+		return errors.New("transaction already started")
+	}
+
+	data := make([][]string, len(df.Data))
+	for i, row := range df.Data {
+		data[i] = slices.Clone(row)
+	}
+
+	df.tx = &transaction{
+		columns: slices.Clone(df.Columns),
+		data:    data,
+	}
+
+	return nil
+}
+
+// InTransaction reports whether a transaction is open
+func (df *DataFrame) InTransaction() bool {
+	return df.tx != nil
+}
+
+// saveChanges saves the DataFrame to a CSV file after a change made by an *AndSave helper.
+// In a transaction nothing is written, the changes are saved by Commit
+func (df *DataFrame) saveChanges(filePath string) error {
+
+	if df.tx != nil {
+		return nil
+	}
+
+	return df.SaveCSV(filePath)
+}
+
+// Commit validates the staged changes and writes the DataFrame to a CSV file once.
+// If validation or writing fails, the file is left untouched and the transaction stays
+// open, so the changes can be fixed or rolled back
+func (df *DataFrame) Commit(filePath string) error {
+
+	if df.tx == nil {
+		return errors.New("no transaction started")
+	}
+
+	if err := df.validateStaged(); err != nil {
+		return err
+	}
+
+	tx := df.tx
+	df.tx = nil
+
+	if err := df.SaveCSV(filePath); err != nil {
+		df.tx = tx
+		return err
+	}
+
+	if df.journal == nil {
+		return nil
+	}
+
+	err := df.journal.record(tx.ops...)
+	df.markSaved()
+
+	return err
+}
+
+// Rollback discards the staged changes and restores the DataFrame to its state at Begin
+func (df *DataFrame) Rollback() error {
+
+	if df.tx == nil {
+		return errors.New("no transaction started")
+	}
+
+	df.Columns = df.tx.columns
+	df.Data = df.tx.data
+	df.tx = nil
+
+	return df.rebuildIndex()
+}
+
+// validateStaged checks that every row has a value for each column and that the rows
+// added or changed in the transaction do not share a key with another row
+func (df *DataFrame) validateStaged() error {
+
+	// Rows with extra fields are kept as LoadCSV read them
+	for i, row := range df.Data {
+		if len(row) < len(df.Columns) {
+			return fmt.Errorf("row %d: row length does not match number of columns", i+1)
+		}
+	}
+
+	if len(df.keyColumns) == 0 {
+		return nil
+	}
+
+	// df.Data may have been changed directly during the transaction
+	if err := df.rebuildIndex(); err != nil {
+		return err
+	}
+
+	indexes, err := df.keyIndexes()
+	if err != nil {
+		return err
+	}
+
+	for _, op := range df.tx.ops {
+		if op.Kind == OpAdd || op.Kind == OpUpdate {
+			if len(df.findRows(df.rowKey(op.Row, indexes), indexes)) > 1 {
+				return fmt.Errorf("%w: %v", ErrDuplicateKey, op.Row)
+			}
+		}
+	}
+
+	return nil
+}
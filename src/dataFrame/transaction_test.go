@@ -0,0 +1,149 @@
+package dataFrame
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestTransaction_commitSavesOnce(t *testing.T) {
+
+	file := writeTestFile(t, "Word;Translation\ncat;кошка\n")
+
+	df := NewDataFrame(';')
+	_ = df.LoadCSV(file)
+	_ = df.SetKey(CompareFold, "Word")
+	df.SetAppendMode(true)
+	df.SetJournal(NewJournal())
+
+	if err := df.Begin(); err != nil {
+		t.Fatalf("Begin error: %v", err)
+	}
+
+	if err := df.Begin(); err == nil {
+		t.Error("Expected error for nested Begin")
+	}
+
+	for _, row := range [][]string{{"dog", "собака"}, {"Cat", "кот"}, {"fox", "лиса"}} {
+		if err := df.AddUniqueRowAndSave(row, file); err != nil {
+			t.Fatalf("AddUniqueRowAndSave error: %v", err)
+		}
+	}
+
+	if _, err := df.DeleteRowsWhereAndSave("Word", Equals("fox"), file); err != nil {
+		t.Fatalf("DeleteRowsWhereAndSave error: %v", err)
+	}
+
+	if err := df.SaveCSV(file); !errors.Is(err, ErrInTransaction) {
+		t.Errorf("Expected ErrInTransaction from SaveCSV, got %v", err)
+	}
+
+	if err := df.AppendCSV([]string{"owl", "сова"}, file); !errors.Is(err, ErrInTransaction) {
+		t.Errorf("Expected ErrInTransaction from AppendCSV, got %v", err)
+	}
+
+	if data, _ := os.ReadFile(file); string(data) != "Word;Translation\ncat;кошка\n" {
+		t.Errorf("File written before Commit: %q", data)
+	}
+
+	if err := df.Commit(file); err != nil {
+		t.Fatalf("Commit error: %v", err)
+	}
+
+	if df.InTransaction() {
+		t.Error("Transaction still open after Commit")
+	}
+
+	df2 := NewDataFrame(';')
+	_ = df2.LoadCSV(file)
+
+	expected := [][]string{{"cat", "кошка"}, {"dog", "собака"}}
+	if !reflect.DeepEqual(df2.Data, expected) {
+		t.Errorf("Expected %v, got %v", expected, df2.Data)
+	}
+
+	// The whole batch is undone at once
+	if len(df.Journal().Undo) != 1 {
+		t.Fatalf("Expected one journal entry, got %d", len(df.Journal().Undo))
+	}
+
+	if ok, err := df.Undo(); !ok || err != nil {
+		t.Fatalf("Undo error: %v", err)
+	}
+
+	if !reflect.DeepEqual(df.Data, [][]string{{"cat", "кошка"}}) {
+		t.Errorf("Undo did not revert the batch: %v", df.Data)
+	}
+}
+
+func TestTransaction_rollback(t *testing.T) {
+
+	df := NewDataFrame(';')
+	df.Columns = []string{"Word", "Translation"}
+	df.Data = [][]string{{"cat", "кошка"}, {"dog", "собака"}}
+	_ = df.SetKey(CompareFold, "Word")
+	_ = df.EnableIndex()
+
+	_ = df.Begin()
+	_ = df.UpdateCell(0, "Translation", "кот")
+	_ = df.DeleteRow(1)
+	_ = df.AddRow([]string{"fox", "лиса"})
+
+	if err := df.Rollback(); err != nil {
+		t.Fatalf("Rollback error: %v", err)
+	}
+
+	expected := [][]string{{"cat", "кошка"}, {"dog", "собака"}}
+	if !reflect.DeepEqual(df.Data, expected) {
+		t.Errorf("Expected %v, got %v", expected, df.Data)
+	}
+
+	if rows, _ := df.FindRowsByKey("fox"); len(rows) != 0 {
+		t.Errorf("Index not restored: %v", rows)
+	}
+
+	if err := df.Rollback(); err == nil {
+		t.Error("Expected error for Rollback without Begin")
+	}
+
+	if err := df.Commit("unused.csv"); err == nil {
+		t.Error("Expected error for Commit without Begin")
+	}
+}
+
+func TestTransaction_invalidCommitKeepsFile(t *testing.T) {
+
+	file := writeTestFile(t, "Word;Translation\ncat;кошка\n")
+
+	df := NewDataFrame(';')
+	_ = df.LoadCSV(file)
+	_ = df.SetKey(CompareFold, "Word")
+
+	_ = df.Begin()
+	_ = df.AddRow([]string{"CAT", "кот"})
+
+	if err := df.Commit(file); !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("Expected ErrDuplicateKey, got %v", err)
+	}
+
+	df.Data = append(df.Data[:1], []string{"dog"})
+
+	if err := df.Commit(file); err == nil {
+		t.Error("Expected error for short row")
+	}
+
+	if !df.InTransaction() {
+		t.Error("Transaction closed after failed Commit")
+	}
+
+	if data, _ := os.ReadFile(file); string(data) != "Word;Translation\ncat;кошка\n" {
+		t.Errorf("File changed by failed Commit: %q", data)
+	}
+
+	_ = df.Rollback()
+
+	if !reflect.DeepEqual(df.Data, [][]string{{"cat", "кошка"}}) {
+		t.Errorf("Rollback after failed Commit: %v", df.Data)
+	}
+}
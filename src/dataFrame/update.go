@@ -0,0 +1,86 @@
+package dataFrame
+
+import (
+	"errors"
+	"slices"
+)
+
+// ErrDuplicateKey is returned when a change would give a row the key of another row
+var ErrDuplicateKey = errors.New("a row with the same key already exists")
+
+// UpdateRow replaces the values of the row at index.
+// If a key is set, the new key must not belong to another row
+func (df *DataFrame) UpdateRow(index int, values []string) error {
+
+	if index < 0 || index >= len(df.Data) {
+		return errors.New("index out of range")
+	}
+
+	if len(values) != len(df.Columns) {
+		return errors.New("row length does not match number of columns")
+	}
+
+	if len(df.keyColumns) > 0 {
+
+		indexes, err := df.keyIndexes()
+		if err != nil {
+			return err
+		}
+
+		for _, i := range df.findRows(df.rowKey(values, indexes), indexes) {
+			if i != index {
+				return ErrDuplicateKey
+			}
+		}
+	}
+
+	op := Operation{Kind: OpUpdate, Index: index, Row: slices.Clone(values), Old: slices.Clone(df.Data[index])}
+	if err := df.record(op); err != nil {
+		return err
+	}
+
+	df.Data[index] = values
+
+	return df.rebuildIndex()
+}
+
+// UpdateCell sets the value of column in the row at index.
+// If a key is set, the new key must not belong to another row
+func (df *DataFrame) UpdateCell(index int, column string, value string) error {
+
+	colIdx := df.columnIndex(column)
+
+	if colIdx == -1 {
+		return errors.New("column name not found")
+	}
+
+	if index < 0 || index >= len(df.Data) {
+		return errors.New("index out of range")
+	}
+
+	values := make([]string, len(df.Columns))
+	copy(values, df.Data[index])
+	values[colIdx] = value
+
+	return df.UpdateRow(index, values)
+}
+
+// UpdateRowAndSave replaces the values of the row at index and saves the DataFrame to a CSV file
+func (df *DataFrame) UpdateRowAndSave(index int, values []string, filePath string) error {
+
+	if err := df.UpdateRow(index, values); err != nil {
+		return err
+	}
+
+	return df.saveChanges(filePath)
+}
+
+// UpdateCellAndSave sets the value of a cell and saves the DataFrame to a CSV file
+func (df *DataFrame) UpdateCellAndSave(index int, column string, value string, filePath string) error {
+
+	if err := df.UpdateCell(index, column, value); err != nil {
+		return err
+	}
+
+	return df.saveChanges(filePath)
+}
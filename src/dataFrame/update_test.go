@@ -0,0 +1,79 @@
+package dataFrame
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestUpdateRow(t *testing.T) {
+
+	df := NewDataFrame(';')
+	df.Columns = []string{"Word", "Translation"}
+	df.Data = [][]string{{"cat", "кошка"}, {"dog", "собака"}}
+	_ = df.SetKey(CompareFold, "Word")
+	_ = df.EnableIndex()
+
+	if err := df.UpdateRow(0, []string{"kitten", "котёнок"}); err != nil {
+		t.Fatalf("UpdateRow error: %v", err)
+	}
+
+	if rows, _ := df.FindRowsByKey("kitten"); !reflect.DeepEqual(rows, []int{0}) {
+		t.Errorf("Expected index to follow the update, got %v", rows)
+	}
+
+	if err := df.UpdateRow(0, []string{"DOG", "пёс"}); !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("Expected ErrDuplicateKey, got %v", err)
+	}
+
+	// A row may keep its own key
+	if err := df.UpdateRow(1, []string{"Dog", "пёс"}); err != nil {
+		t.Errorf("UpdateRow with own key error: %v", err)
+	}
+
+	if err := df.UpdateRow(5, []string{"a", "b"}); err == nil {
+		t.Error("Expected error for index out of range")
+	}
+
+	if err := df.UpdateRow(0, []string{"a"}); err == nil {
+		t.Error("Expected error for short row")
+	}
+}
+
+func TestUpdateCell(t *testing.T) {
+
+	df := NewDataFrame(';')
+	df.Columns = []string{"Word", "Translation"}
+	df.Data = [][]string{{"cat", ""}}
+
+	if err := df.UpdateCell(0, "Translation", "кошка"); err != nil {
+		t.Fatalf("UpdateCell error: %v", err)
+	}
+
+	if df.Data[0][1] != "кошка" {
+		t.Errorf("Cell not updated: %v", df.Data)
+	}
+
+	if err := df.UpdateCell(0, "Missing", "x"); err == nil {
+		t.Error("Expected error for unknown column")
+	}
+}
+
+func TestUpdateCellAndSave(t *testing.T) {
+
+	file := writeTestFile(t, "Word;Translation\ncat;\n")
+
+	df := NewDataFrame(';')
+	_ = df.LoadCSV(file)
+
+	if err := df.UpdateCellAndSave(0, "Translation", "кошка", file); err != nil {
+		t.Fatalf("UpdateCellAndSave error: %v", err)
+	}
+
+	df2 := NewDataFrame(';')
+	_ = df2.LoadCSV(file)
+
+	if df2.Data[0][1] != "кошка" {
+		t.Errorf("Update not saved: %v", df2.Data)
+	}
+}
@@ -0,0 +1,270 @@
+package dataFrame
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// IssueKind identifies the kind of problem found in a deck
+type IssueKind string
+
+const (
+	IssueParse        IssueKind = "parse error"
+	IssueHeader       IssueKind = "header"
+	IssueFieldCount   IssueKind = "field count"
+	IssueEmptyKey     IssueKind = "empty key"
+	IssueDuplicateKey IssueKind = "duplicate key"
+	IssueBOM          IssueKind = "BOM"
+	IssueEncoding     IssueKind = "invalid UTF-8"
+)
+
+// utf8BOM is the byte order mark some editors put at the start of UTF-8 files
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// Issue is a single problem found in a deck
+type Issue struct {
+	Line    int       // line number in the file, starting from 1
+	Kind    IssueKind // kind of the problem
+	Message string    // human-readable description
+}
+
+// String returns the issue as "line N: kind: message"
+func (i Issue) String() string {
+	return fmt.Sprintf("line %d: %s: %s", i.Line, i.Kind, i.Message)
+}
+
+// ValidationReport lists the problems found in a deck by Validate
+type ValidationReport struct {
+	Path     string   // path of the checked file
+	Encoding Encoding // detected encoding of the file
+	Rows     int      // number of data rows read
+	Issues   []Issue  // problems in the order they appear in the file
+}
+
+// OK reports whether no problems were found
+func (r *ValidationReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// Lines returns every issue as a separate line of text
+func (r *ValidationReport) Lines() []string {
+
+	lines := make([]string, len(r.Issues))
+
+	for i, issue := range r.Issues {
+		lines[i] = issue.String()
+	}
+
+	return lines
+}
+
+func (r *ValidationReport) add(line int, kind IssueKind, format string, args ...any) {
+	r.Issues = append(r.Issues, Issue{Line: line, Kind: kind, Message: fmt.Sprintf(format, args...)})
+}
+
+// Validate checks a CSV file without loading it into the DataFrame and reports every
+// problem found instead of stopping at the first one: parse errors, rows with the wrong
+// number of fields, empty and duplicate keys, a header that differs from schema
+// (not checked if schema is nil; the optional columns may follow it), a UTF-8 BOM
+// and invalid UTF-8.
+// Keys are taken from the key columns of the DataFrame, or the first column if no key is set
+func (df *DataFrame) Validate(filePath string, schema []string, optional ...string) (*ValidationReport, error) {
+
+	filePath, err := getTrueFilepath(filePath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ValidationReport{Path: filePath, Encoding: DetectEncoding(data)}
+
+	data, err = decode(data, report.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	// The byte order mark of UTF-16 is expected, it is decoded as a UTF-8 one
+	if bytes.HasPrefix(data, utf8BOM) {
+		if report.Encoding == EncodingUTF8 {
+			report.add(1, IssueBOM, "file starts with a UTF-8 byte order mark")
+		}
+		data = data[len(utf8BOM):]
+	}
+
+	directives, data, offset := splitDirectives(data)
+
+	delimiter := df.delimiter
+	if df.sniff {
+		delimiter = SniffDialect(data).Delimiter
+	}
+
+	if i := findDirective(directives, DirectiveSeparator); i != -1 {
+		if r := parseSeparator(directives[i].Value); r != AutoDelimiter {
+			delimiter = r
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+
+	var header []string
+	var keyIdx []int
+
+	// With a #columns directive the file has no header row
+	if i := findDirective(directives, DirectiveColumns); i != -1 {
+		header = splitFields(directives[i].Value, delimiter)
+		report.checkHeader(header, schema, optional, delimiter, i+1)
+		keyIdx = validationKeyIndexes(header, df.keyColumns)
+	}
+	keys := make(map[string]int)
+
+	comparison := df.comparison
+	if comparison == nil {
+		comparison = CompareExact
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			report.add(parseErr.StartLine+offset, IssueParse, "%v", parseErr.Err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		line += offset
+
+		for _, field := range record {
+			if !utf8.ValidString(field) {
+				report.add(line, IssueEncoding, "field %q is not valid UTF-8", field)
+				break
+			}
+		}
+
+		if header == nil {
+			header = record
+			report.checkHeader(header, schema, optional, delimiter, line)
+			keyIdx = validationKeyIndexes(header, df.keyColumns)
+			continue
+		}
+
+		report.Rows++
+
+		if len(record) != len(header) {
+			report.add(line, IssueFieldCount, "%d fields, expected %d", len(record), len(header))
+		}
+
+		values := make([]string, len(keyIdx))
+		empty := false
+
+		for i, idx := range keyIdx {
+			if idx < len(record) {
+				values[i] = record[idx]
+			}
+			if strings.TrimSpace(values[i]) == "" {
+				empty = true
+			}
+		}
+
+		if empty {
+			report.add(line, IssueEmptyKey, "key field is empty")
+			continue
+		}
+
+		key := strings.Join(mapStrings(values, comparison), keySeparator)
+
+		if first, ok := keys[key]; ok {
+			report.add(line, IssueDuplicateKey, "%q duplicates line %d", strings.Join(values, " "), first)
+		} else {
+			keys[key] = line
+		}
+	}
+
+	if header == nil {
+		report.add(1, IssueHeader, "file is empty")
+	}
+
+	return report, nil
+}
+
+// checkHeader reports a header that does not match schema (not checked if schema is nil)
+func (r *ValidationReport) checkHeader(header, schema, optional []string, delimiter rune, line int) {
+
+	if schema != nil && !headerMatches(header, schema, optional) {
+		r.add(line, IssueHeader, "header is %q, expected %q",
+			strings.Join(header, string(delimiter)), strings.Join(schema, string(delimiter)))
+	}
+}
+
+// validationKeyIndexes returns the indexes of the key columns in header,
+// or the first column if no key columns are set or found
+func validationKeyIndexes(header []string, keyColumns []string) []int {
+
+	var indexes []int
+
+	for _, name := range keyColumns {
+		for i, column := range header {
+			if column == name {
+				indexes = append(indexes, i)
+				break
+			}
+		}
+	}
+
+	if len(indexes) != len(keyColumns) || len(indexes) == 0 {
+		return []int{0}
+	}
+
+	return indexes
+}
+
+// mapStrings applies f to every value
+func mapStrings(values []string, f func(string) string) []string {
+
+	result := make([]string, len(values))
+
+	for i, v := range values {
+		result[i] = f(v)
+	}
+
+	return result
+}
+
+// headerMatches reports whether header is schema followed by some of the optional
+// columns in any order, each at most once
+func headerMatches(header, schema, optional []string) bool {
+
+	if len(header) < len(schema) || !slices.Equal(header[:len(schema)], schema) {
+		return false
+	}
+
+	extra := header[len(schema):]
+
+	for i, column := range extra {
+		if !slices.Contains(optional, column) || slices.Contains(extra[:i], column) {
+			return false
+		}
+	}
+
+	return true
+}
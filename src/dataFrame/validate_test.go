@@ -0,0 +1,125 @@
+package dataFrame
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, contents string) string {
+
+	file := filepath.Join(t.TempDir(), "deck.csv")
+
+	if err := os.WriteFile(file, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return file
+}
+
+func TestValidate_clean(t *testing.T) {
+
+	file := writeTestFile(t, "Word;Translation\ncat;кошка\ndog;собака\n")
+
+	report, err := NewDataFrame(';').Validate(file, []string{"Word", "Translation"})
+	if err != nil {
+		t.Fatalf("Validate error: %v", err)
+	}
+
+	if !report.OK() || report.Rows != 2 {
+		t.Errorf("Expected clean report with 2 rows, got %d rows: %v", report.Rows, report.Lines())
+	}
+}
+
+func TestValidate_reportsEveryIssue(t *testing.T) {
+
+	file := writeTestFile(t, "\xEF\xBB\xBFTerm;Translation\n"+
+		"cat;кошка\n"+
+		"dog\n"+
+		";пусто\n"+
+		"Cat;кот\n"+
+		"bad\xff;x\n"+
+		"a \"quote;b\n"+
+		"fish;рыба\n")
+
+	df := NewDataFrame(';')
+	df.comparison = CompareFold
+
+	report, err := df.Validate(file, []string{"Word", "Translation"})
+	if err != nil {
+		t.Fatalf("Validate error: %v", err)
+	}
+
+	want := []struct {
+		line int
+		kind IssueKind
+	}{
+		{1, IssueBOM},
+		{1, IssueHeader},
+		{3, IssueFieldCount},
+		{4, IssueEmptyKey},
+		{5, IssueDuplicateKey},
+		{6, IssueEncoding},
+		{7, IssueParse},
+	}
+
+	if len(report.Issues) != len(want) {
+		t.Fatalf("Expected %d issues, got %v", len(want), report.Lines())
+	}
+
+	for i, w := range want {
+		got := report.Issues[i]
+		if got.Line != w.line || got.Kind != w.kind {
+			t.Errorf("Issue %d: got %v, want line %d %s", i, got, w.line, w.kind)
+		}
+	}
+
+	// Parsing continues after an error
+	if report.Rows != 6 {
+		t.Errorf("Expected 6 rows read, got %d", report.Rows)
+	}
+}
+
+func TestValidate_emptyFile(t *testing.T) {
+
+	report, err := NewDataFrame(';').Validate(writeTestFile(t, ""), nil)
+	if err != nil {
+		t.Fatalf("Validate error: %v", err)
+	}
+
+	if report.OK() || report.Issues[0].Kind != IssueHeader {
+		t.Errorf("Expected empty file issue, got %v", report.Lines())
+	}
+}
+
+func TestValidate_missingFile(t *testing.T) {
+
+	if _, err := NewDataFrame(';').Validate(filepath.Join(t.TempDir(), "none.csv"), nil); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+func TestValidate_optionalColumns(t *testing.T) {
+
+	schema := []string{"Word", "Translation"}
+
+	tests := map[string]bool{
+		"Word;Translation\n":           true,
+		"Word;Translation;Deck;Tags\n": true,
+		"Word;Translation;Tags;Tags\n": false,
+		"Word;Translation;Notes\n":     false,
+		"Word;Tags;Translation\n":      false,
+	}
+
+	for contents, ok := range tests {
+
+		report, err := NewDataFrame(';').Validate(writeTestFile(t, contents), schema, "Tags", "Deck")
+		if err != nil {
+			t.Fatalf("Validate error: %v", err)
+		}
+
+		if report.OK() != ok {
+			t.Errorf("%q: expected OK %v, got %v", contents, ok, report.Lines())
+		}
+	}
+}
@@ -0,0 +1,410 @@
+package deckConfig
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Your-RoGr/DeckBuilder/src/dataFrame"
+)
+
+var settingsPath = "~/.local/share/DeckBuilder/data/deckSettings.csv"
+var settingsColumns = []string{"Deck", "Key", "Value"}
+var historyDir = "~/.local/share/DeckBuilder/history"
+var lockDir = "~/.local/share/DeckBuilder/locks"
+
+// Keys of the deck settings
+const (
+	KeyDelimiter = "delimiter"
+	KeyHeader    = "header"
+	KeyTags      = "tags"
+	KeyDeck      = "deck"
+	KeyEncoding  = "encoding"
+	KeyNormalize = "normalize"
+	KeyFuzzy     = "fuzzy"
+	KeyFields    = "fields"
+)
+
+// DefaultNormalization is the normalization of the entries of decks that do not set one
+const DefaultNormalization = "nfc,spaces"
+
+// DefaultFuzzy is the number of edits up to which an existing word is shown as
+// similar to a new one, for decks that do not set it
+const DefaultFuzzy = 2
+
+// Names of the optional deck columns holding the Anki tags and target deck of a note
+const (
+	ColumnTags = "Tags"
+	ColumnDeck = "Deck"
+)
+
+// Definition describes a deck setting that can be edited by the user
+type Definition struct {
+	Key         string                   // key the value is stored under
+	Description string                   // prompt shown to the user
+	Check       func(value string) error // validates a new value, nil accepts anything
+}
+
+// Definitions lists the deck settings in the order they are edited
+var Definitions = []Definition{
+	{
+		Key:         KeyDelimiter,
+		Description: "Delimiter (auto, ';', ',', tab, '|')",
+		Check:       checkDelimiter,
+	},
+	{
+		Key:         KeyHeader,
+		Description: "Header (auto - as in the file, plain, anki)",
+		Check:       checkHeader,
+	},
+	{
+		Key:         KeyTags,
+		Description: "Default tags, separated by spaces (Tags column)",
+	},
+	{
+		Key:         KeyDeck,
+		Description: "Anki deck of new entries (Deck column)",
+	},
+	{
+		Key:         KeyEncoding,
+		Description: "Encoding of a deck not in UTF-8 (keep, utf-8 - convert)",
+		Check:       checkEncoding,
+	},
+	{
+		Key:         KeyNormalize,
+		Description: "Normalization of entries (nfc, quotes, spaces, lower, punct or none; default nfc,spaces)",
+		Check:       checkNormalize,
+	},
+	{
+		Key:         KeyFuzzy,
+		Description: "Warn about similar words up to this many typos (0 - off; default 2)",
+		Check:       checkFuzzy,
+	},
+	{
+		Key:         KeyFields,
+		Description: "Extra columns between Translation and Tags, separated by commas (e.g. Example,Audio)",
+		Check:       checkFields,
+	},
+}
+
+// Config holds the settings of a single deck
+type Config struct {
+	deck   string
+	values map[string]string
+}
+
+// Open loads the settings of the deck at deckPath.
+// A deck without saved settings gets an empty Config
+func Open(deckPath string) (*Config, error) {
+
+	config := &Config{
+		deck:   deckPath,
+		values: make(map[string]string),
+	}
+
+	df, err := loadSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range df.Data {
+		if len(row) == len(settingsColumns) && row[0] == deckPath {
+			config.values[row[1]] = row[2]
+		}
+	}
+
+	return config, nil
+}
+
+// loadSettings loads the settings of all decks, or returns an empty DataFrame
+// if no settings have been saved yet
+func loadSettings() (*dataFrame.DataFrame, error) {
+
+	df := dataFrame.NewDataFrame(';')
+	df.Columns = settingsColumns
+
+	err := df.LoadCSV(settingsPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return df, nil
+	}
+
+	return df, err
+}
+
+// Save writes the settings of the deck, replacing the previously saved ones
+func (c *Config) Save() error {
+
+	df, err := loadSettings()
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(df.Data)+len(c.values))
+
+	for _, row := range df.Data {
+		if len(row) > 0 && row[0] != c.deck {
+			rows = append(rows, row)
+		}
+	}
+
+	keys := make([]string, 0, len(c.values))
+	for key := range c.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		rows = append(rows, []string{c.deck, key, c.values[key]})
+	}
+
+	df.Data = rows
+
+	if err := dataFrame.CreateNewCSV(settingsPath, settingsColumns, ';'); err != nil {
+		return err
+	}
+
+	return df.SaveCSV(settingsPath)
+}
+
+// Delete removes the saved settings of the deck, leaving the other decks' settings as they are
+func (c *Config) Delete() error {
+
+	clear(c.values)
+
+	return c.Save()
+}
+
+// Get returns the value of a setting, or "" if it is not set
+func (c *Config) Get(key string) string {
+	return c.values[key]
+}
+
+// Set validates and sets the value of a setting. An empty value resets it to the default
+func (c *Config) Set(key, value string) error {
+
+	if value == "" {
+		delete(c.values, key)
+		return nil
+	}
+
+	for _, def := range Definitions {
+		if def.Key == key && def.Check != nil {
+			if err := def.Check(value); err != nil {
+				return err
+			}
+		}
+	}
+
+	c.values[key] = value
+
+	return nil
+}
+
+// Delimiter returns the delimiter chosen for the deck,
+// or dataFrame.AutoDelimiter if it should be detected
+func (c *Config) Delimiter() rune {
+
+	switch value := c.Get(KeyDelimiter); value {
+	case "", "auto":
+		return dataFrame.AutoDelimiter
+	case "tab":
+		return '\t'
+	default:
+		return []rune(value)[0]
+	}
+}
+
+// Tags returns the default tags of new entries
+func (c *Config) Tags() string {
+	return NormalizeTags(c.Get(KeyTags))
+}
+
+// NormalizeTags joins space-separated tags with single spaces, dropping repeated ones
+func NormalizeTags(tags string) string {
+
+	var result []string
+
+	for _, tag := range strings.Fields(tags) {
+		if !slices.Contains(result, tag) {
+			result = append(result, tag)
+		}
+	}
+
+	return strings.Join(result, " ")
+}
+
+// Normalization returns the normalization applied to the entries of the deck
+func (c *Config) Normalization() dataFrame.Pipeline {
+
+	value := c.Get(KeyNormalize)
+	if value == "" {
+		value = DefaultNormalization
+	}
+
+	pipeline, err := dataFrame.ParsePipeline(value)
+	if err != nil {
+		pipeline, _ = dataFrame.ParsePipeline(DefaultNormalization)
+	}
+
+	return pipeline
+}
+
+// Fuzzy returns the number of edits up to which existing words are shown as similar
+// to a new one, 0 if similar words are not searched
+func (c *Config) Fuzzy() int {
+
+	value, err := strconv.Atoi(c.Get(KeyFuzzy))
+	if err != nil {
+		return DefaultFuzzy
+	}
+
+	return value
+}
+
+// Fields returns the names of the extra columns of the deck, such as the note fields
+// of an imported Anki package that have no column of their own
+func (c *Config) Fields() []string {
+
+	var fields []string
+
+	for _, name := range strings.Split(c.Get(KeyFields), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			fields = append(fields, name)
+		}
+	}
+
+	return fields
+}
+
+// HeaderFormat returns the format the beginning of the deck file is written in
+func (c *Config) HeaderFormat() dataFrame.HeaderFormat {
+
+	switch c.Get(KeyHeader) {
+	case "plain":
+		return dataFrame.HeaderPlain
+	case "anki":
+		return dataFrame.HeaderAnki
+	default:
+		return dataFrame.HeaderAsLoaded
+	}
+}
+
+// deckFileName returns a file name derived from deckPath, unique for every deck
+func deckFileName(deckPath, ext string) string {
+
+	sum := sha1.Sum([]byte(deckPath))
+
+	return hex.EncodeToString(sum[:8]) + ext
+}
+
+// HistoryPath returns the path of the undo history of the deck at deckPath
+func HistoryPath(deckPath string) string {
+	return filepath.Join(historyDir, deckFileName(deckPath, ".json"))
+}
+
+// LockPath returns the path of the lock file of the deck at deckPath
+func LockPath(deckPath string) string {
+	return filepath.Join(lockDir, deckFileName(deckPath, ".lock"))
+}
+
+// OpenJournal opens the undo history of the deck
+func (c *Config) OpenJournal() (*dataFrame.Journal, error) {
+	return dataFrame.OpenJournal(HistoryPath(c.deck))
+}
+
+// Lock takes the lock of the deck, so that no other DeckBuilder writes it at the same time.
+// Returns dataFrame.ErrLocked if the deck is locked
+func (c *Config) Lock() (*dataFrame.FileLock, error) {
+	return dataFrame.LockFile(LockPath(c.deck))
+}
+
+// NewDataFrame creates an empty DataFrame set up according to the deck settings
+func (c *Config) NewDataFrame() *dataFrame.DataFrame {
+
+	df := dataFrame.NewDataFrame(c.Delimiter())
+	df.SetHeaderFormat(c.HeaderFormat())
+	df.SetColumnDirective(dataFrame.DirectiveTagsColumn, ColumnTags)
+	df.SetColumnDirective(dataFrame.DirectiveDeckColumn, ColumnDeck)
+
+	if c.Get(KeyEncoding) == "utf-8" {
+		df.SetSaveEncoding(dataFrame.EncodingUTF8)
+	}
+
+	return df
+}
+
+func checkDelimiter(value string) error {
+
+	switch value {
+	case "auto", "tab", ";", ",", "|":
+		return nil
+	}
+
+	return errors.New("delimiter must be one of: auto, ';', ',', tab, '|'")
+}
+
+func checkHeader(value string) error {
+
+	switch value {
+	case "auto", "plain", "anki":
+		return nil
+	}
+
+	return errors.New("header must be one of: auto, plain, anki")
+}
+
+func checkEncoding(value string) error {
+
+	switch value {
+	case "keep", "utf-8":
+		return nil
+	}
+
+	return errors.New("encoding must be one of: keep, utf-8")
+}
+
+func checkNormalize(value string) error {
+
+	_, err := dataFrame.ParsePipeline(value)
+
+	return err
+}
+
+func checkFuzzy(value string) error {
+
+	if n, err := strconv.Atoi(value); err != nil || n < 0 || n > 9 {
+		return errors.New("the number of typos must be from 0 to 9")
+	}
+
+	return nil
+}
+
+func checkFields(value string) error {
+
+	seen := make(map[string]bool)
+
+	for _, name := range strings.Split(value, ",") {
+
+		name = strings.TrimSpace(name)
+
+		switch {
+		case name == "":
+			return errors.New("column names cannot be empty")
+		case name == ColumnTags || name == ColumnDeck:
+			return fmt.Errorf("%s is not an extra column", name)
+		case seen[name]:
+			return fmt.Errorf("column %s is listed twice", name)
+		}
+
+		seen[name] = true
+	}
+
+	return nil
+}
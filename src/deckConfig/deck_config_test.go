@@ -0,0 +1,287 @@
+package deckConfig
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/Your-RoGr/DeckBuilder/src/dataFrame"
+	"github.com/Your-RoGr/DeckBuilder/src/testUtils"
+)
+
+func useTempSettings(t *testing.T) {
+
+	old := settingsPath
+	settingsPath = testUtils.TempCSVPath(t)
+
+	t.Cleanup(func() {
+		settingsPath = old
+	})
+}
+
+func TestOpen_withoutSettings(t *testing.T) {
+
+	useTempSettings(t)
+
+	config, err := Open("deck.csv")
+	if err != nil {
+		t.Fatalf("Open error: %v", err)
+	}
+
+	if config.Delimiter() != dataFrame.AutoDelimiter {
+		t.Errorf("Expected auto delimiter by default, got %q", config.Delimiter())
+	}
+}
+
+func TestSaveAndOpen(t *testing.T) {
+
+	useTempSettings(t)
+
+	first, _ := Open("first.csv")
+	if err := first.Set(KeyDelimiter, "tab"); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+	if err := first.Save(); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+
+	second, _ := Open("second.csv")
+	_ = second.Set(KeyDelimiter, ";")
+	if err := second.Save(); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+
+	// Saving again replaces the previous values
+	_ = first.Set(KeyDelimiter, ",")
+	if err := first.Save(); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+
+	first, _ = Open("first.csv")
+	second, _ = Open("second.csv")
+
+	if first.Delimiter() != ',' || second.Delimiter() != ';' {
+		t.Errorf("Unexpected delimiters %q and %q", first.Delimiter(), second.Delimiter())
+	}
+
+	_ = first.Set(KeyDelimiter, "")
+	if first.Get(KeyDelimiter) != "" {
+		t.Error("Expected empty value to reset the setting")
+	}
+}
+
+func TestDelete(t *testing.T) {
+
+	useTempSettings(t)
+
+	for _, deck := range []string{"first.csv", "second.csv"} {
+		config, _ := Open(deck)
+		_ = config.Set(KeyDelimiter, "tab")
+		if err := config.Save(); err != nil {
+			t.Fatalf("Save error: %v", err)
+		}
+	}
+
+	first, _ := Open("first.csv")
+	if err := first.Delete(); err != nil {
+		t.Fatalf("Delete error: %v", err)
+	}
+
+	first, _ = Open("first.csv")
+	second, _ := Open("second.csv")
+
+	if first.Get(KeyDelimiter) != "" || second.Get(KeyDelimiter) != "tab" {
+		t.Errorf("Unexpected settings after Delete: %q and %q", first.Get(KeyDelimiter), second.Get(KeyDelimiter))
+	}
+}
+
+func TestSet_rejectsInvalidDelimiter(t *testing.T) {
+
+	config := &Config{values: map[string]string{}}
+
+	if err := config.Set(KeyDelimiter, "x"); err == nil {
+		t.Error("Expected error for invalid delimiter")
+	}
+}
+
+func TestNormalization(t *testing.T) {
+
+	config := &Config{values: map[string]string{}}
+
+	if got := config.Normalization().String(); got != DefaultNormalization {
+		t.Errorf("Expected the default normalization, got %s", got)
+	}
+
+	if err := config.Set(KeyNormalize, "nfc,shout"); err == nil {
+		t.Error("Expected error for an unknown normalization")
+	}
+
+	if err := config.Set(KeyNormalize, "lower punct"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := config.Normalization().String(); got != "lower,punct" {
+		t.Errorf("Expected lower,punct, got %s", got)
+	}
+
+	if err := config.Set(KeyNormalize, "none"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := config.Normalization(); len(got) != 0 {
+		t.Errorf("Expected no normalization, got %s", got)
+	}
+}
+
+func TestFuzzy(t *testing.T) {
+
+	config := &Config{values: map[string]string{}}
+
+	if config.Fuzzy() != DefaultFuzzy {
+		t.Errorf("Expected %d by default, got %d", DefaultFuzzy, config.Fuzzy())
+	}
+
+	for _, value := range []string{"-1", "10", "two"} {
+		if err := config.Set(KeyFuzzy, value); err == nil {
+			t.Errorf("Expected error for %q", value)
+		}
+	}
+
+	if err := config.Set(KeyFuzzy, "0"); err != nil || config.Fuzzy() != 0 {
+		t.Errorf("Expected 0 to turn the warnings off, got %d, %v", config.Fuzzy(), err)
+	}
+}
+
+func TestFields(t *testing.T) {
+
+	config := &Config{values: map[string]string{}}
+
+	if fields := config.Fields(); fields != nil {
+		t.Errorf("Expected no extra columns by default, got %v", fields)
+	}
+
+	for _, value := range []string{"Example,,Audio", "Example,Tags", "Audio,Audio"} {
+		if err := config.Set(KeyFields, value); err == nil {
+			t.Errorf("Expected error for %q", value)
+		}
+	}
+
+	if err := config.Set(KeyFields, "Example, Audio"); err != nil {
+		t.Fatalf("Set error: %v", err)
+	}
+
+	if fields := config.Fields(); !reflect.DeepEqual(fields, []string{"Example", "Audio"}) {
+		t.Errorf("Unexpected extra columns: %v", fields)
+	}
+}
+
+func TestNewDataFrame_usesDelimiter(t *testing.T) {
+
+	config := &Config{values: map[string]string{KeyDelimiter: "|"}}
+
+	if df := config.NewDataFrame(); df.Delimiter() != '|' {
+		t.Errorf("Expected '|' delimiter, got %q", df.Delimiter())
+	}
+}
+
+func TestNewDataFrame_usesHeaderFormat(t *testing.T) {
+
+	config := &Config{values: map[string]string{}}
+
+	if err := config.Set(KeyHeader, "csv"); err == nil {
+		t.Error("Expected error for invalid header format")
+	}
+
+	_ = config.Set(KeyHeader, "anki")
+
+	file := testUtils.TempCSVPath(t)
+	df := config.NewDataFrame()
+	df.Columns = []string{"Word", "Translation"}
+
+	if err := df.SaveCSV(file); err != nil {
+		t.Fatalf("SaveCSV error: %v", err)
+	}
+
+	df = dataFrame.NewDataFrame(dataFrame.AutoDelimiter)
+	_ = df.LoadCSV(file)
+
+	if _, ok := df.Directive(dataFrame.DirectiveColumns); !ok {
+		t.Errorf("Expected deck to be saved with Anki directives, got %v", df.Directives())
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+
+	if tags := NormalizeTags("  verbs english\tverbs  "); tags != "verbs english" {
+		t.Errorf("Unexpected tags %q", tags)
+	}
+}
+
+func TestNewDataFrame_writesTagsColumn(t *testing.T) {
+
+	config := &Config{values: map[string]string{KeyHeader: "anki"}}
+
+	df := config.NewDataFrame()
+	df.Columns = []string{"Word", "Translation", ColumnTags, ColumnDeck}
+
+	file := testUtils.TempCSVPath(t)
+	_ = df.SaveCSV(file)
+
+	df = dataFrame.NewDataFrame(dataFrame.AutoDelimiter)
+	_ = df.LoadCSV(file)
+
+	tags, _ := df.Directive(dataFrame.DirectiveTagsColumn)
+	deck, _ := df.Directive(dataFrame.DirectiveDeckColumn)
+
+	if tags != "3" || deck != "4" {
+		t.Errorf("Expected tags column 3 and deck column 4, got %q and %q", tags, deck)
+	}
+}
+
+func TestNewDataFrame_convertsEncoding(t *testing.T) {
+
+	config := &Config{values: map[string]string{}}
+
+	if err := config.Set(KeyEncoding, "cp1251"); err == nil {
+		t.Error("Expected error for invalid encoding")
+	}
+
+	file := testUtils.TempCSVPath(t)
+	if err := os.WriteFile(file, []byte("Word;Translation\ncat;\xea\xee\xf8\xea\xe0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_ = config.Set(KeyEncoding, "utf-8")
+
+	df := config.NewDataFrame()
+	_ = df.LoadCSV(file)
+	_ = df.SaveCSV(file)
+
+	if data, _ := os.ReadFile(file); string(data) != "Word;Translation\ncat;кошка\n" {
+		t.Errorf("Expected deck converted to UTF-8, got %q", data)
+	}
+}
+
+func TestHistoryPath(t *testing.T) {
+
+	a, b := HistoryPath("/decks/a.csv"), HistoryPath("/decks/b.csv")
+
+	if a != HistoryPath("/decks/a.csv") {
+		t.Error("Expected history path to be stable")
+	}
+
+	if a == b {
+		t.Error("Expected different decks to have different history paths")
+	}
+}
+
+func TestLockPath(t *testing.T) {
+
+	if LockPath("/decks/a.csv") == LockPath("/decks/b.csv") {
+		t.Error("Expected different decks to have different lock paths")
+	}
+
+	if LockPath("/decks/a.csv") == HistoryPath("/decks/a.csv") {
+		t.Error("Expected the lock and the history of a deck to be different files")
+	}
+}
@@ -0,0 +1,113 @@
+package exporter
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Your-RoGr/DeckBuilder/src/dataFrame"
+)
+
+// Format describes a format decks can be exported to
+type Format struct {
+	Name      string // name used in the menu and on the command line
+	Extension string // extension of exported files, with the dot
+	Write     func(w io.Writer, df *dataFrame.DataFrame, title string) error
+}
+
+// formats lists the registered formats in the order they are offered
+var formats = []Format{
+	{Name: "json", Extension: ".json", Write: writeJSON},
+	{Name: "tsv", Extension: ".tsv", Write: writeTSV},
+	{Name: "markdown", Extension: ".md", Write: writeMarkdown},
+	{Name: "html", Extension: ".html", Write: writeHTML},
+	{Name: "apkg", Extension: ".apkg", Write: writeAPKG},
+	{Name: "apkg-reversed", Extension: ".apkg", Write: writeAPKGReversed},
+}
+
+// Register adds a format, replacing a registered format with the same name
+func Register(format Format) {
+
+	if i := slices.IndexFunc(formats, func(f Format) bool { return f.Name == format.Name }); i != -1 {
+		formats[i] = format
+		return
+	}
+
+	formats = append(formats, format)
+}
+
+// Names returns the names of the registered formats
+func Names() []string {
+
+	names := make([]string, len(formats))
+	for i, f := range formats {
+		names[i] = f.Name
+	}
+
+	return names
+}
+
+// Lookup returns the format registered under name, ignoring case
+func Lookup(name string) (Format, error) {
+
+	for _, f := range formats {
+		if strings.EqualFold(f.Name, name) {
+			return f, nil
+		}
+	}
+
+	return Format{}, fmt.Errorf("unknown export format '%s' (%s)", name, strings.Join(Names(), ", "))
+}
+
+// OutputPath returns the path of the file a deck at deckPath is exported to by default:
+// the deck path with the extension of the format
+func OutputPath(deckPath string, format Format) string {
+	return strings.TrimSuffix(deckPath, filepath.Ext(deckPath)) + format.Extension
+}
+
+// Title returns the title of an exported deck: the name of its file without the extension
+func Title(deckPath string) string {
+
+	name := filepath.Base(deckPath)
+
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// Export writes df to w in format
+func Export(w io.Writer, df *dataFrame.DataFrame, format Format, title string) error {
+
+	if len(df.Columns) == 0 {
+		return errors.New("the deck has no columns")
+	}
+
+	buf := bufio.NewWriter(w)
+
+	if err := format.Write(buf, df, title); err != nil {
+		return err
+	}
+
+	return buf.Flush()
+}
+
+// ExportFile writes df in format to the file at filePath, replacing it if it exists.
+// A failed export leaves the previous file intact
+func ExportFile(filePath string, df *dataFrame.DataFrame, format Format, title string) error {
+
+	return dataFrame.WriteFileAtomic(filePath, func(w io.Writer) error {
+		return Export(w, df, format, title)
+	})
+}
+
+// cell returns the value at column idx of row, or "" for a short row
+func cell(row []string, idx int) string {
+
+	if idx < len(row) {
+		return row[idx]
+	}
+
+	return ""
+}
@@ -0,0 +1,205 @@
+package exporter
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Your-RoGr/DeckBuilder/src/dataFrame"
+)
+
+func newDeck() *dataFrame.DataFrame {
+
+	df := dataFrame.NewDataFrame(';')
+	df.Columns = []string{"Word", "Translation", "Tags"}
+	df.Data = [][]string{
+		{"cat", "кошка", "animals"},
+		{"a|b", "line\nbreak", `tab	and \ <b>`},
+	}
+
+	return df
+}
+
+func export(t *testing.T, name string, df *dataFrame.DataFrame) string {
+
+	format, err := Lookup(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Export(&buf, df, format, "animals & more"); err != nil {
+		t.Fatalf("Export error: %v", err)
+	}
+
+	return buf.String()
+}
+
+func TestExport_json(t *testing.T) {
+
+	out := export(t, "json", newDeck())
+
+	var rows []map[string]string
+	if err := json.Unmarshal([]byte(out), &rows); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, out)
+	}
+
+	expected := []map[string]string{
+		{"Word": "cat", "Translation": "кошка", "Tags": "animals"},
+		{"Word": "a|b", "Translation": "line\nbreak", "Tags": "tab\tand \\ <b>"},
+	}
+
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Rows mismatch: %v", rows)
+	}
+
+	if !strings.HasPrefix(out, "[\n  {\"Word\": \"cat\", \"Translation\": \"кошка\", \"Tags\": \"animals\"},\n") {
+		t.Errorf("Expected keys in column order, got:\n%s", out)
+	}
+}
+
+func TestExport_jsonEmpty(t *testing.T) {
+
+	df := dataFrame.NewDataFrame(';')
+	df.Columns = []string{"Word"}
+
+	if out := export(t, "json", df); out != "[]\n" {
+		t.Errorf("Expected an empty array, got %q", out)
+	}
+}
+
+func TestExport_tsv(t *testing.T) {
+
+	out := export(t, "tsv", newDeck())
+	expected := "Word\tTranslation\tTags\n" +
+		"cat\tкошка\tanimals\n" +
+		"a|b\tline\\nbreak\ttab\\tand \\\\ <b>\n"
+
+	if out != expected {
+		t.Errorf("TSV mismatch:\n got %q\nwant %q", out, expected)
+	}
+}
+
+func TestExport_markdown(t *testing.T) {
+
+	out := export(t, "Markdown", newDeck())
+	expected := "| Word | Translation | Tags |\n" +
+		"| --- | --- | --- |\n" +
+		"| cat | кошка | animals |\n" +
+		"| a\\|b | line<br>break | tab\tand \\\\ <b> |\n"
+
+	if out != expected {
+		t.Errorf("Markdown mismatch:\n got %q\nwant %q", out, expected)
+	}
+}
+
+func TestExport_html(t *testing.T) {
+
+	out := export(t, "html", newDeck())
+
+	for _, part := range []string{
+		"<!DOCTYPE html>",
+		"<title>animals &amp; more</title>",
+		"<tr><th>Word</th><th>Translation</th><th>Tags</th></tr>",
+		"<tr><td>cat</td><td>кошка</td><td>animals</td></tr>",
+		"<td>line<br>break</td><td>tab\tand \\ &lt;b&gt;</td>",
+		"</html>\n",
+	} {
+		if !strings.Contains(out, part) {
+			t.Errorf("Expected %q in:\n%s", part, out)
+		}
+	}
+}
+
+func TestExport_apkg(t *testing.T) {
+
+	for _, name := range []string{"apkg", "apkg-reversed"} {
+
+		out := export(t, name, newDeck())
+
+		archive, err := zip.NewReader(strings.NewReader(out), int64(len(out)))
+		if err != nil {
+			t.Fatalf("%s: not a zip archive: %v", name, err)
+		}
+
+		var files []string
+		for _, file := range archive.File {
+			files = append(files, file.Name)
+		}
+
+		if !reflect.DeepEqual(files, []string{"collection.anki2", "media"}) {
+			t.Errorf("%s: unexpected files %v", name, files)
+		}
+	}
+}
+
+func TestLookup_unknown(t *testing.T) {
+
+	if _, err := Lookup("xlsx"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestRegister(t *testing.T) {
+
+	old := formats
+	formats = append([]Format(nil), formats...)
+	t.Cleanup(func() { formats = old })
+
+	Register(Format{Name: "json", Extension: ".js"})
+	Register(Format{Name: "csv", Extension: ".csv"})
+
+	if !reflect.DeepEqual(Names(), []string{"json", "tsv", "markdown", "html", "apkg", "apkg-reversed", "csv"}) {
+		t.Errorf("Names mismatch: %v", Names())
+	}
+
+	if format, _ := Lookup("json"); format.Extension != ".js" {
+		t.Errorf("Expected the format to be replaced, got %v", format.Extension)
+	}
+}
+
+func TestExportFile(t *testing.T) {
+
+	format, _ := Lookup("markdown")
+	path := OutputPath(filepath.Join(t.TempDir(), "animals.csv"), format)
+
+	if filepath.Base(path) != "animals.md" {
+		t.Errorf("Unexpected output path: %s", path)
+	}
+
+	if err := ExportFile(path, newDeck(), format, Title(path)); err != nil {
+		t.Fatalf("ExportFile error: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "| Word |") {
+		t.Errorf("Unexpected file contents: %q", data)
+	}
+}
+
+func TestExportFile_keepsFileOnError(t *testing.T) {
+
+	format, _ := Lookup("markdown")
+	path := filepath.Join(t.TempDir(), "animals.md")
+
+	if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ExportFile(path, dataFrame.NewDataFrame(';'), format, Title(path)); err == nil {
+		t.Fatal("Expected error for a deck with no columns")
+	}
+
+	if data, _ := os.ReadFile(path); string(data) != "old" {
+		t.Errorf("Expected the previous file to stay, got %q", data)
+	}
+
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("Expected no temporary files left, got %d entries", len(entries))
+	}
+}
@@ -0,0 +1,220 @@
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/Your-RoGr/DeckBuilder/src/anki"
+	"github.com/Your-RoGr/DeckBuilder/src/dataFrame"
+)
+
+// writeJSON writes the rows as an array of objects keyed by column name,
+// with the keys in the order of the columns
+func writeJSON(w io.Writer, df *dataFrame.DataFrame, title string) error {
+
+	if len(df.Data) == 0 {
+		_, err := io.WriteString(w, "[]\n")
+		return err
+	}
+
+	if _, err := io.WriteString(w, "[\n"); err != nil {
+		return err
+	}
+
+	for i, row := range df.Data {
+
+		fields := make([]string, len(df.Columns))
+
+		for j, column := range df.Columns {
+
+			key, err := marshalString(column)
+			if err != nil {
+				return err
+			}
+
+			value, err := marshalString(cell(row, j))
+			if err != nil {
+				return err
+			}
+
+			fields[j] = key + ": " + value
+		}
+
+		separator := ","
+		if i == len(df.Data)-1 {
+			separator = ""
+		}
+
+		if _, err := fmt.Fprintf(w, "  {%s}%s\n", strings.Join(fields, ", "), separator); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w, "]\n")
+	return err
+}
+
+// marshalString encodes s as a JSON string without escaping HTML characters
+func marshalString(s string) (string, error) {
+
+	var b strings.Builder
+
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(s); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// tsvEscaper escapes the characters that cannot appear in a TSV field
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// writeTSV writes the columns and rows separated by tabs. Tabs, line breaks and
+// backslashes in values are written as \t, \n, \r and \\
+func writeTSV(w io.Writer, df *dataFrame.DataFrame, title string) error {
+
+	line := func(values []string) error {
+
+		fields := make([]string, len(df.Columns))
+		for i := range fields {
+			fields[i] = tsvEscaper.Replace(cell(values, i))
+		}
+
+		_, err := io.WriteString(w, strings.Join(fields, "\t")+"\n")
+		return err
+	}
+
+	if err := line(df.Columns); err != nil {
+		return err
+	}
+
+	for _, row := range df.Data {
+		if err := line(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// markdownEscaper escapes the characters that would break a Markdown table row
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "|", `\|`, "\r\n", "<br>", "\n", "<br>", "\r", "<br>")
+
+// writeMarkdown writes the rows as a Markdown table
+func writeMarkdown(w io.Writer, df *dataFrame.DataFrame, title string) error {
+
+	line := func(values []string) error {
+
+		fields := make([]string, len(df.Columns))
+		for i := range fields {
+			fields[i] = markdownEscaper.Replace(cell(values, i))
+		}
+
+		_, err := io.WriteString(w, "| "+strings.Join(fields, " | ")+" |\n")
+		return err
+	}
+
+	if err := line(df.Columns); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, strings.Repeat("| --- ", len(df.Columns))+"|\n"); err != nil {
+		return err
+	}
+
+	for _, row := range df.Data {
+		if err := line(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// htmlHead is the beginning of a standalone HTML document, followed by the title
+const htmlHead = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+</style>
+</head>
+<body>
+<table>
+`
+
+// htmlFoot is the end of the HTML document started by htmlHead
+const htmlFoot = `</table>
+</body>
+</html>
+`
+
+// writeHTML writes the rows as a table in a standalone HTML document
+func writeHTML(w io.Writer, df *dataFrame.DataFrame, title string) error {
+
+	line := func(tag string, values []string) error {
+
+		var b strings.Builder
+		b.WriteString("<tr>")
+
+		for i := range df.Columns {
+			value := strings.ReplaceAll(html.EscapeString(cell(values, i)), "\n", "<br>")
+			fmt.Fprintf(&b, "<%s>%s</%s>", tag, value, tag)
+		}
+
+		b.WriteString("</tr>\n")
+
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, htmlHead, html.EscapeString(title)); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, "<thead>\n"); err != nil {
+		return err
+	}
+
+	if err := line("th", df.Columns); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, "</thead>\n<tbody>\n"); err != nil {
+		return err
+	}
+
+	for _, row := range df.Data {
+		if err := line("td", row); err != nil {
+			return err
+		}
+	}
+
+	if _, err := io.WriteString(w, "</tbody>\n"); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, htmlFoot)
+	return err
+}
+
+// writeAPKG writes the rows as an Anki package with a deck named title,
+// so that it is imported into Anki without mapping the fields
+func writeAPKG(w io.Writer, df *dataFrame.DataFrame, title string) error {
+	return anki.WritePackage(w, df, anki.Options{Deck: title})
+}
+
+// writeAPKGReversed writes an Anki package like writeAPKG with a second card
+// for every note, from the translation to the word
+func writeAPKGReversed(w io.Writer, df *dataFrame.DataFrame, title string) error {
+	return anki.WritePackage(w, df, anki.Options{Deck: title, Reversed: true})
+}
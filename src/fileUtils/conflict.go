@@ -0,0 +1,69 @@
+package fileUtils
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Your-RoGr/DeckBuilder/src/appUtils"
+	"github.com/Your-RoGr/DeckBuilder/src/dataFrame"
+	"github.com/Your-RoGr/DeckBuilder/src/deckConfig"
+)
+
+// ErrAborted is returned by ResolveConflict when the user chose to drop the changes
+var ErrAborted = errors.New("the changes were discarded, the deck was reloaded")
+
+// ErrDeckLocked is returned when the deck is being changed by another DeckBuilder
+var ErrDeckLocked = errors.New("the deck is open in another DeckBuilder, try again later")
+
+// ResolveConflict handles err returned when df was saved to path. If the deck was changed
+// outside of DeckBuilder, the user chooses to merge both changes, overwrite the deck,
+// or abort, in which case the deck is reloaded without the unsaved changes and ErrAborted
+// is returned. The question is asked again until one of them is chosen.
+// Other errors are returned unchanged
+func ResolveConflict(df *dataFrame.DataFrame, path string, err error) error {
+
+	if !errors.Is(err, dataFrame.ErrFileChanged) {
+		return err
+	}
+
+	for {
+		input, _ := appUtils.GetInput(
+			"The deck was changed outside of DeckBuilder. Reload and merge (m), overwrite (o) or abort (a)? ",
+			true,
+		)
+
+		switch strings.ToLower(strings.TrimSpace(input)) {
+		case "m":
+			err = df.MergeCSV(path)
+		case "o":
+			err = df.ResetFingerprint(path)
+		case "a":
+			// Only the history of the unsaved changes is dropped
+			if err := df.DiscardChanges(path); err != nil {
+				return err
+			}
+
+			return ErrAborted
+		default:
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return df.SaveCSV(path)
+	}
+}
+
+// LockDeck takes the lock of the deck described by config.
+// Returns ErrDeckLocked if another DeckBuilder holds it
+func LockDeck(config *deckConfig.Config) (*dataFrame.FileLock, error) {
+
+	lock, err := config.Lock()
+	if errors.Is(err, dataFrame.ErrLocked) {
+		return nil, ErrDeckLocked
+	}
+
+	return lock, err
+}
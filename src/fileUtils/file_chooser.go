@@ -4,13 +4,22 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strings"
 
 	"github.com/Your-RoGr/DeckBuilder/src/appUtils"
 	"github.com/Your-RoGr/DeckBuilder/src/dataFrame"
+	"github.com/Your-RoGr/DeckBuilder/src/deckConfig"
 	"github.com/nsf/termbox-go"
 )
 
+// DeckColumns are the columns of a newly created deck
+var DeckColumns = []string{"Word", "Translation"}
+
+// OptionalDeckColumns may follow DeckColumns to carry the Anki tags and target deck of a note
+var OptionalDeckColumns = []string{deckConfig.ColumnTags, deckConfig.ColumnDeck}
+
 // FileChooser implements file/folder selection in the terminal
 type FileChooser struct {
 	currentDir   string
@@ -167,7 +176,14 @@ func (fc *FileChooser) Start() (string, error) {
 					if ok && name != "" {
 
 						fullpath := filepath.Join(fc.currentDir, name)
-						err := dataFrame.CreateNewCSV(fullpath, []string{"Word", "Translation"}, ';')
+						columns := DeckColumns
+
+						answer, _ := appUtils.GetInput("Add Tags and Deck columns for Anki? (y/N): ", false)
+						if strings.EqualFold(strings.TrimSpace(answer), "y") {
+							columns = append(slices.Clone(DeckColumns), OptionalDeckColumns...)
+						}
+
+						err := dataFrame.CreateNewCSV(fullpath, columns, ';')
 
 						if err == nil {
 							fc.readDir()
@@ -1,21 +1,34 @@
 package fileUtils
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
-	"os"
+	"slices"
 	"strings"
 
 	"github.com/Your-RoGr/DeckBuilder/src/appUtils"
 	"github.com/Your-RoGr/DeckBuilder/src/dataFrame"
+	"github.com/Your-RoGr/DeckBuilder/src/deckConfig"
+	"github.com/nsf/termbox-go"
 )
 
+const wordPrompt = "Enter a word to add (Ctrl+Z - undo; Ctrl+Y - redo): "
+
+// similarLimit is the number of similar entries shown before a word is added
+const similarLimit = 3
+
 // WordAdder — structure for selecting a file and adding words to it.
 type WordAdder struct {
-	mode     string
-	filePath string // Path to the selected file
-	df       *dataFrame.DataFrame
+	mode      string
+	filePath  string // Path to the selected file
+	df        *dataFrame.DataFrame
+	tags      string             // tags of the last entry, offered for the next one
+	deck      string             // Anki deck of new entries
+	normalize dataFrame.Pipeline // normalization of the entered values
+	fuzzy     int                // typos up to which similar entries are shown, 0 - off
+
+	KeyColumns []string             // Columns used to detect duplicates (the first column by default)
+	Comparison dataFrame.Comparison // How key values are compared (normalized and case-insensitive by default)
 }
 
 // Start — allows selecting a file (txt/csv) and adding new words separated by ;.
@@ -23,8 +36,38 @@ func (wa *WordAdder) Start(mode string, path string) error {
 
 	wa.mode = mode
 	wa.filePath = path
-	wa.df = dataFrame.NewDataFrame(';')
-	wa.df.LoadCSV(path)
+	config, err := deckConfig.Open(path)
+	if err != nil {
+		return err
+	}
+
+	lock, err := LockDeck(config)
+	if errors.Is(err, ErrDeckLocked) {
+		appUtils.GetInput(err.Error(), false)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	journal, err := config.OpenJournal()
+	if err != nil {
+		return err
+	}
+
+	wa.df = config.NewDataFrame()
+	if err := wa.df.LoadCSV(path); err != nil {
+		return err
+	}
+
+	wa.df.SetJournal(journal)
+	wa.df.SetAppendMode(true)
+	wa.normalize = config.Normalization()
+	wa.fuzzy = config.Fuzzy()
+	wa.setKey()
+	wa.tags = config.Tags()
+	wa.deck = config.Get(deckConfig.KeyDeck)
 
 	// Loop for entering words
 	switch wa.mode {
@@ -57,15 +100,20 @@ func (wa *WordAdder) Start(mode string, path string) error {
 
 func wordMode(wa *WordAdder) error {
 
-	word, ok := appUtils.GetInput("Enter a word to add: ", true)
-	if !ok || strings.TrimSpace(word) == "" {
+	word, key, ok := appUtils.GetInputWithHotkeys(wordPrompt, "", true, termbox.KeyCtrlZ, termbox.KeyCtrlY)
+	if key != 0 {
+		return wa.undo(key == termbox.KeyCtrlY)
+	}
+
+	word = wa.normalize.Apply(word)
+	if !ok || word == "" {
 		return errors.New("break")
 	}
 
-	word = strings.TrimSpace(word)
+	row := wa.newRow(word, "")
 
-	// Check if the word already exists in the file
-	exists, err := wordExistsInFile(wa.filePath, word)
+	// Check if the word already exists in the deck
+	exists, err := wa.exists(row)
 	if err != nil {
 		appUtils.GetInput("Error: "+err.Error(), false)
 		return err
@@ -76,7 +124,19 @@ func wordMode(wa *WordAdder) error {
 		return nil
 	}
 
-	err = wa.df.AddUniqueRowAndSave([]string{word, ""}, wa.filePath)
+	if !wa.confirmSimilar(word) {
+		return nil
+	}
+
+	if !wa.enterTags(row) {
+		return nil
+	}
+
+	err = ResolveConflict(wa.df, wa.filePath, wa.df.AddUniqueRowAndSave(row, wa.filePath))
+	if errors.Is(err, ErrAborted) {
+		appUtils.GetInput(err.Error(), false)
+		return nil
+	}
 	if err != nil {
 		appUtils.GetInput("Error: "+err.Error(), false)
 		return err
@@ -88,15 +148,18 @@ func wordMode(wa *WordAdder) error {
 
 func wordTranslateMode(wa *WordAdder) error {
 
-	word, ok := appUtils.GetInput("Enter a word to add: ", true)
-	if !ok || strings.TrimSpace(word) == "" {
-		return errors.New("break")
+	word, key, ok := appUtils.GetInputWithHotkeys(wordPrompt, "", true, termbox.KeyCtrlZ, termbox.KeyCtrlY)
+	if key != 0 {
+		return wa.undo(key == termbox.KeyCtrlY)
 	}
 
-	word = strings.TrimSpace(word)
+	word = wa.normalize.Apply(word)
+	if !ok || word == "" {
+		return errors.New("break")
+	}
 
-	// Check if the word already exists in the file
-	exists, err := wordExistsInFile(wa.filePath, word)
+	// Check if the word already exists in the deck
+	exists, err := wa.exists(wa.newRow(word, ""))
 	if err != nil {
 		appUtils.GetInput("Error: "+err.Error(), false)
 		return err
@@ -107,14 +170,39 @@ func wordTranslateMode(wa *WordAdder) error {
 		return nil
 	}
 
+	if !wa.confirmSimilar(word) {
+		return nil
+	}
+
 	translate, ok := appUtils.GetInput("Enter a translate to add: ", true)
 	if !ok || strings.TrimSpace(word) == "" {
 		return errors.New("strings.TrimSpace(word) == \"\"")
 	}
 
-	translate = strings.TrimSpace(translate)
+	translate = wa.normalize.Apply(translate)
+	row := wa.newRow(word, translate)
 
-	err = wa.df.AddUniqueRowAndSave([]string{word, translate}, wa.filePath)
+	// The key may include the translation, so check the full row as well
+	exists, err = wa.exists(row)
+	if err != nil {
+		appUtils.GetInput("Error: "+err.Error(), false)
+		return err
+	}
+
+	if exists {
+		appUtils.GetInput(fmt.Sprintf("'%s - %s' already exists!", word, translate), false)
+		return nil
+	}
+
+	if !wa.enterTags(row) {
+		return nil
+	}
+
+	err = ResolveConflict(wa.df, wa.filePath, wa.df.AddUniqueRowAndSave(row, wa.filePath))
+	if errors.Is(err, ErrAborted) {
+		appUtils.GetInput(err.Error(), false)
+		return nil
+	}
 	if err != nil {
 		appUtils.GetInput("Error: "+err.Error(), false)
 		return err
@@ -124,27 +212,160 @@ func wordTranslateMode(wa *WordAdder) error {
 	return nil
 }
 
-// wordExistsInFile — checks if a word already exists in the file (case-insensitive)
-func wordExistsInFile(filePath, targetWord string) (bool, error) {
+// undo reverts the last change of the deck, or repeats the last undone change if redo is true
+func (wa *WordAdder) undo(redo bool) error {
+
+	var ok bool
+	var err error
+	action := "undo"
+
+	if redo {
+		ok, err = wa.df.RedoAndSave(wa.filePath)
+		action = "redo"
+	} else {
+		ok, err = wa.df.UndoAndSave(wa.filePath)
+	}
 
-	f, err := os.Open(filePath)
+	err = ResolveConflict(wa.df, wa.filePath, err)
+	if errors.Is(err, ErrAborted) {
+		appUtils.GetInput(err.Error(), false)
+		return nil
+	}
 	if err != nil {
-		return false, err
+		appUtils.GetInput("Error: "+err.Error(), false)
+		return err
+	}
+
+	if !ok {
+		appUtils.GetInput(fmt.Sprintf("Nothing to %s!", action), false)
+		return nil
+	}
+
+	appUtils.GetInput(fmt.Sprintf("%s done!", action), false)
+	return nil
+}
+
+// setKey configures duplicate detection on the loaded deck
+func (wa *WordAdder) setKey() {
+
+	if len(wa.df.Columns) == 0 {
+		return
+	}
+
+	columns := wa.KeyColumns
+	if len(columns) == 0 {
+		columns = wa.df.Columns[:1]
+	}
+
+	comparison := wa.Comparison
+	if comparison == nil {
+		comparison = wa.normalize.Comparison()
 	}
-	defer f.Close()
 
-	targetWordLower := strings.ToLower(strings.TrimSpace(targetWord))
+	if wa.df.SetKey(comparison, columns...) == nil {
+		wa.df.EnableIndex()
+	}
+}
+
+// newRow builds a deck row from the entered word and translation.
+// The optional Tags and Deck columns get the current defaults
+func (wa *WordAdder) newRow(word, translation string) []string {
+
+	row := make([]string, max(len(wa.df.Columns), 2))
+	row[0], row[1] = word, translation
+
+	for i, column := range wa.df.Columns {
+		switch {
+		case i < 2:
+		case column == deckConfig.ColumnTags:
+			row[i] = wa.tags
+		case column == deckConfig.ColumnDeck:
+			row[i] = wa.deck
+		}
+	}
+
+	return row
+}
+
+// enterTags asks for the tags of row if the deck has a Tags column.
+// The tags entered become the default for the next entry. Returns false if cancelled
+func (wa *WordAdder) enterTags(row []string) bool {
+
+	idx := slices.Index(wa.df.Columns, deckConfig.ColumnTags)
+	if idx == -1 {
+		return true
+	}
+
+	tags, ok := appUtils.GetInputWithValue("Enter tags separated by spaces: ", wa.tags, false)
+	if !ok {
+		return false
+	}
 
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		// Split line by semicolon
-		words := strings.Split(scanner.Text(), ";")
-		for _, w := range words {
-			w = strings.ToLower(strings.TrimSpace(w))
-			if w == targetWordLower {
-				return true, nil
+	wa.tags = deckConfig.NormalizeTags(tags)
+	row[idx] = wa.tags
+
+	return true
+}
+
+// similarEntries describes the entries whose key is within wa.fuzzy typos of word,
+// closest first, as "word - translation"
+func (wa *WordAdder) similarEntries(word string) ([]string, error) {
+
+	if wa.fuzzy == 0 || len(wa.df.Columns) == 0 {
+		return nil, nil
+	}
+
+	column := wa.df.Columns[0]
+	if len(wa.KeyColumns) > 0 {
+		column = wa.KeyColumns[0]
+	}
+
+	matches, err := wa.df.NearMatches(column, word, wa.fuzzy, similarLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]string, len(matches))
+
+	for i, match := range matches {
+
+		var values []string
+		for _, value := range wa.df.Data[match.Index][:min(2, len(wa.df.Data[match.Index]))] {
+			if value != "" {
+				values = append(values, value)
 			}
 		}
+
+		entries[i] = strings.Join(values, " - ")
 	}
-	return false, scanner.Err()
+
+	return entries, nil
+}
+
+// confirmSimilar shows the entries similar to word and asks whether to add it anyway.
+// Returns true if there are none or the user confirms
+func (wa *WordAdder) confirmSimilar(word string) bool {
+
+	entries, err := wa.similarEntries(word)
+	if err != nil || len(entries) == 0 {
+		return true
+	}
+
+	answer, _ := appUtils.GetInput(
+		fmt.Sprintf("Similar: %s. Add '%s' anyway? (y/N): ", strings.Join(entries, "; "), word),
+		false,
+	)
+
+	return strings.EqualFold(strings.TrimSpace(answer), "y")
+}
+
+// exists — checks if an entry with the same key as row is already in the deck
+func (wa *WordAdder) exists(row []string) (bool, error) {
+
+	idx, err := wa.df.FindDuplicate(row)
+	if err != nil {
+		return false, err
+	}
+
+	return idx != -1, nil
 }
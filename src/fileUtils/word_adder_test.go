@@ -1,6 +1,7 @@
 package fileUtils
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/Your-RoGr/DeckBuilder/src/dataFrame"
@@ -70,13 +71,99 @@ func TestWordTranslateModeLogic(t *testing.T) {
 	})
 }
 
-func TestWordExistsInFileLogic(t *testing.T) {
-	testUtils.NoPanic(t, func() {
+func TestWordAdderExists(t *testing.T) {
+
+	wa := &WordAdder{df: dataFrame.NewDataFrame(';')}
+	wa.df.Columns = []string{"Word", "Translation"}
+	wa.df.Data = [][]string{{"Hello", "Привет"}}
+	wa.setKey()
+
+	cases := []struct {
+		row  []string
+		want bool
+	}{
+		{[]string{" hello ", ""}, true},
+		{[]string{"Привет", ""}, false},
+		{[]string{"Word", ""}, false},
+	}
+
+	for _, c := range cases {
+
+		got, err := wa.exists(c.row)
+		if err != nil {
+			t.Fatalf("exists(%v) error: %v", c.row, err)
+		}
+
+		if got != c.want {
+			t.Errorf("exists(%v) = %v, want %v", c.row, got, c.want)
+		}
+	}
+}
 
-		termbox.Init()
-		defer termbox.Close()
+func TestWordAdderNewRow(t *testing.T) {
 
-		filePath := testUtils.TempCSVPath(t)
-		_, _ = wordExistsInFile(filePath, "aaa")
-	})
+	wa := &WordAdder{df: dataFrame.NewDataFrame(';'), tags: "english verbs", deck: "Words"}
+	wa.df.Columns = []string{"Word", "Translation", "Deck", "Tags"}
+
+	got := wa.newRow("go", "идти")
+	want := []string{"go", "идти", "Words", "english verbs"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("newRow = %v, want %v", got, want)
+	}
+
+	wa.df.Columns = []string{"Word", "Translation"}
+
+	if got := wa.newRow("go", ""); !reflect.DeepEqual(got, []string{"go", ""}) {
+		t.Errorf("newRow without optional columns = %v", got)
+	}
+}
+
+func TestWordAdderExists_normalized(t *testing.T) {
+
+	pipeline, _ := dataFrame.ParsePipeline("nfc,spaces,punct")
+
+	wa := &WordAdder{df: dataFrame.NewDataFrame(';'), normalize: pipeline}
+	wa.df.Columns = []string{"Word", "Translation"}
+	wa.df.Data = [][]string{{"Hello world", "Привет, мир"}, {"café", "кафе"}}
+	wa.setKey()
+
+	for _, word := range []string{"  hello   world", "Hello world!", "cafe\u0301"} {
+		if got, _ := wa.exists([]string{word, ""}); !got {
+			t.Errorf("Expected %q to be a duplicate after normalization", word)
+		}
+	}
+}
+
+func TestWordAdderSimilarEntries(t *testing.T) {
+
+	wa := &WordAdder{df: dataFrame.NewDataFrame(';'), fuzzy: 2}
+	wa.df.Columns = []string{"Word", "Translation"}
+	wa.df.Data = [][]string{{"colour", "цвет"}, {"run", ""}, {"receive", "получать"}}
+	wa.setKey()
+
+	cases := map[string][]string{
+		"color":   {"colour - цвет"},
+		"to run":  {"run"},
+		"recieve": {"receive - получать"},
+		"walk":    {},
+	}
+
+	for word, want := range cases {
+
+		got, err := wa.similarEntries(word)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(got) != len(want) || (len(want) > 0 && !reflect.DeepEqual(got, want)) {
+			t.Errorf("similarEntries(%q) = %q, want %q", word, got, want)
+		}
+	}
+
+	wa.fuzzy = 0
+
+	if got, _ := wa.similarEntries("color"); len(got) != 0 {
+		t.Errorf("Expected no similar entries when turned off, got %q", got)
+	}
 }
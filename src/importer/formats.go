@@ -0,0 +1,420 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Your-RoGr/DeckBuilder/src/anki"
+	"github.com/Your-RoGr/DeckBuilder/src/dataFrame"
+)
+
+// utf8BOM is the byte order mark some editors put at the start of UTF-8 files
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// maxLineSize is the longest line the line based formats can read
+const maxLineSize = 1024 * 1024
+
+// readJSON reads an array of objects, whose keys become the fields in the order they are
+// first seen, or an array of arrays of values. Arrays of values inside an object, such
+// as a list of tags, are joined with spaces
+func readJSON(r io.Reader, options Options) (*dataFrame.DataFrame, error) {
+
+	decoder := json.NewDecoder(r)
+
+	if tok, err := decoder.Token(); err != nil || tok != json.Delim('[') {
+		return nil, errors.New("a JSON array of entries is expected")
+	}
+
+	var fields []string
+	var objects []map[string]string
+	var rows [][]string
+
+	for decoder.More() {
+
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, err
+		}
+
+		if bytes.HasPrefix(raw, []byte("{")) {
+
+			keys, object, err := jsonObject(raw)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, key := range keys {
+				if !slices.Contains(fields, key) {
+					fields = append(fields, key)
+				}
+			}
+
+			objects = append(objects, object)
+			continue
+		}
+
+		entry, err := jsonValue(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		if values, ok := entry.([]any); ok {
+			row := make([]string, len(values))
+			for i, v := range values {
+				row[i] = jsonString(v)
+			}
+			rows = append(rows, row)
+		} else {
+			rows = append(rows, []string{jsonString(entry)})
+		}
+	}
+
+	if objects != nil && rows != nil {
+		return nil, errors.New("a JSON array of either objects or arrays is expected")
+	}
+
+	if objects == nil {
+
+		width := 0
+		for _, row := range rows {
+			width = max(width, len(row))
+		}
+
+		return newTable(numberedFields(width), rows), nil
+	}
+
+	rows = make([][]string, len(objects))
+
+	for i, object := range objects {
+		rows[i] = make([]string, len(fields))
+		for j, field := range fields {
+			rows[i][j] = object[field]
+		}
+	}
+
+	return newTable(fields, rows), nil
+}
+
+// jsonObject decodes a JSON object into strings. Returns its keys in the order they appear
+func jsonObject(raw json.RawMessage) ([]string, map[string]string, error) {
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, nil, err
+	}
+
+	var keys []string
+	values := make(map[string]string)
+
+	for decoder.More() {
+
+		tok, err := decoder.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key, _ := tok.(string)
+
+		var v any
+		if err := decoder.Decode(&v); err != nil {
+			return nil, nil, err
+		}
+
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+		values[key] = jsonString(v)
+	}
+
+	return keys, values, nil
+}
+
+// jsonValue decodes a JSON value, keeping numbers as they are written
+func jsonValue(raw json.RawMessage) (any, error) {
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var v any
+	err := decoder.Decode(&v)
+
+	return v, err
+}
+
+// jsonString converts a decoded JSON value to the text of a field
+func jsonString(v any) string {
+
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case []any:
+		values := make([]string, len(v))
+		for i, item := range v {
+			values[i] = jsonString(item)
+		}
+		return strings.Join(values, " ")
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// readTXT reads one entry per line with the fields separated by options.Separator.
+// Empty lines are skipped
+func readTXT(r io.Reader, options Options) (*dataFrame.DataFrame, error) {
+
+	separator := options.Separator
+	if separator == "" {
+		separator = " - "
+	}
+
+	var rows [][]string
+	width := 0
+
+	err := readLines(r, func(line string) {
+
+		if strings.TrimSpace(line) == "" {
+			return
+		}
+
+		row := strings.Split(line, separator)
+		for i := range row {
+			row[i] = strings.TrimSpace(row[i])
+		}
+
+		rows = append(rows, row)
+		width = max(width, len(row))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newTable(numberedFields(width), rows), nil
+}
+
+// tsvUnescaper restores the characters escaped in TSV fields
+var tsvUnescaper = strings.NewReplacer(`\\`, `\`, `\t`, "\t", `\n`, "\n", `\r`, "\r")
+
+// readTSV reads tab separated values with the field names in the first line.
+// \t, \n, \r and \\ in values are read as a tab, line breaks and a backslash
+func readTSV(r io.Reader, options Options) (*dataFrame.DataFrame, error) {
+
+	var fields []string
+	var rows [][]string
+
+	err := readLines(r, func(line string) {
+
+		if line == "" {
+			return
+		}
+
+		row := strings.Split(line, "\t")
+		for i := range row {
+			row[i] = tsvUnescaper.Replace(row[i])
+		}
+
+		if fields == nil {
+			fields = row
+			return
+		}
+
+		rows = append(rows, row)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if fields == nil {
+		return nil, errors.New("the file is empty")
+	}
+
+	return newTable(fields, rows), nil
+}
+
+// readLines calls f for every line of r without the line ending and a leading BOM
+func readLines(r io.Reader, f func(line string)) error {
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	first := true
+
+	for scanner.Scan() {
+
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if first {
+			line = strings.TrimPrefix(line, string(utf8BOM))
+			first = false
+		}
+
+		f(line)
+	}
+
+	return scanner.Err()
+}
+
+// ankiSeparators are the names Anki uses for separators in #separator
+var ankiSeparators = map[string]rune{
+	"comma":     ',',
+	"semicolon": ';',
+	"tab":       '\t',
+	"space":     ' ',
+	"pipe":      '|',
+	"colon":     ':',
+}
+
+// ankiColumns are the names given to the special columns of an Anki export
+var ankiColumns = map[string]string{
+	"guid column":     "GUID",
+	"notetype column": "Notetype",
+	"deck column":     "Deck",
+	"tags column":     "Tags",
+}
+
+var (
+	htmlBreak = regexp.MustCompile(`(?i)<br\s*/?>|</div>\s*<div>`)
+	htmlTag   = regexp.MustCompile(`<[^>]*>`)
+)
+
+// readAnki reads Anki's "Notes in Plain Text" export: header lines such as
+// "#separator:tab" and "#tags column:3" followed by the notes without a header row.
+// With "#html:true" line breaks are kept and other HTML tags are removed
+func readAnki(r io.Reader, options Options) (*dataFrame.DataFrame, error) {
+
+	reader := bufio.NewReaderSize(r, maxLineSize)
+	separator := '\t'
+	isHTML := false
+	var names []string
+	special := map[int]string{}
+
+	if head, _ := reader.Peek(len(utf8BOM)); bytes.Equal(head, utf8BOM) {
+		reader.Discard(len(utf8BOM))
+	}
+
+	for {
+		head, _ := reader.Peek(1)
+		if len(head) == 0 || head[0] != '#' {
+			break
+		}
+
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		key, value, _ := strings.Cut(strings.TrimRight(line[1:], "\r\n"), ":")
+
+		switch key = strings.ToLower(key); key {
+		case "separator":
+			if r, ok := ankiSeparators[strings.ToLower(value)]; ok {
+				separator = r
+			} else if utf8.RuneCountInString(value) == 1 {
+				separator, _ = utf8.DecodeRuneInString(value)
+			}
+		case "html":
+			isHTML = value == "true"
+		case "columns":
+			names = strings.Split(value, string(separator))
+		default:
+			if name, ok := ankiColumns[key]; ok {
+				if n, err := strconv.Atoi(value); err == nil && n > 0 {
+					special[n-1] = name
+				}
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	csvReader := csv.NewReader(reader)
+	csvReader.Comma = separator
+	csvReader.LazyQuotes = true
+	csvReader.FieldsPerRecord = -1
+
+	rows, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	width := len(names)
+	for _, row := range rows {
+		width = max(width, len(row))
+		if isHTML {
+			for i := range row {
+				row[i] = plainText(row[i])
+			}
+		}
+	}
+
+	fields := make([]string, width)
+	n := 0
+
+	for i := range fields {
+		switch {
+		case i < len(names) && names[i] != "":
+			fields[i] = names[i]
+		case special[i] != "":
+			fields[i] = special[i]
+		default:
+			n++
+			fields[i] = fmt.Sprintf("Field %d", n)
+		}
+	}
+
+	return newTable(fields, rows), nil
+}
+
+// readAPKG reads the notes of an Anki package (.apkg or .colpkg), with a column for
+// every field of its notetypes followed by the Tags and Deck columns. The fields are
+// converted to plain text unless options.KeepHTML is set
+func readAPKG(r io.Reader, options Options) (*dataFrame.DataFrame, error) {
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	df, err := anki.ReadPackage(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	if !options.KeepHTML {
+		for _, row := range df.Data {
+			for i := range row {
+				row[i] = plainText(row[i])
+			}
+		}
+	}
+
+	return df, nil
+}
+
+// plainText converts a field with HTML to plain text, keeping line breaks
+func plainText(s string) string {
+
+	s = htmlBreak.ReplaceAllString(s, "\n")
+	s = htmlTag.ReplaceAllString(s, "")
+
+	return html.UnescapeString(s)
+}
@@ -0,0 +1,147 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Your-RoGr/DeckBuilder/src/dataFrame"
+)
+
+// Options holds the settings of formats that need them
+type Options struct {
+	Separator string // separator of the fields of a TXT line, " - " if empty
+	KeepHTML  bool   // keep the HTML of the fields of an Anki package instead of plain text
+}
+
+// Format describes a format decks can be imported from
+type Format struct {
+	Name       string   // name used in the menu
+	Extensions []string // extensions of files in this format, with the dot
+	Read       func(r io.Reader, options Options) (*dataFrame.DataFrame, error)
+}
+
+// formats lists the registered formats in the order they are offered
+var formats = []Format{
+	{Name: "json", Extensions: []string{".json"}, Read: readJSON},
+	{Name: "txt", Extensions: []string{".txt"}, Read: readTXT},
+	{Name: "tsv", Extensions: []string{".tsv", ".tab"}, Read: readTSV},
+	{Name: "anki", Extensions: []string{".txt"}, Read: readAnki},
+	{Name: "apkg", Extensions: []string{".apkg", ".colpkg"}, Read: readAPKG},
+}
+
+// Register adds a format, replacing a registered format with the same name
+func Register(format Format) {
+
+	if i := slices.IndexFunc(formats, func(f Format) bool { return f.Name == format.Name }); i != -1 {
+		formats[i] = format
+		return
+	}
+
+	formats = append(formats, format)
+}
+
+// Names returns the names of the registered formats
+func Names() []string {
+
+	names := make([]string, len(formats))
+	for i, f := range formats {
+		names[i] = f.Name
+	}
+
+	return names
+}
+
+// Lookup returns the format registered under name, ignoring case
+func Lookup(name string) (Format, error) {
+
+	for _, f := range formats {
+		if strings.EqualFold(f.Name, name) {
+			return f, nil
+		}
+	}
+
+	return Format{}, fmt.Errorf("unknown import format '%s' (%s)", name, strings.Join(Names(), ", "))
+}
+
+// Detect guesses the format of the file at filePath from its extension.
+// A .txt file that starts with Anki header lines is an Anki plain text export
+func Detect(filePath string) (Format, error) {
+
+	ext := strings.ToLower(filepath.Ext(filePath))
+
+	if ext == ".txt" {
+
+		head, err := readHead(filePath)
+		if err != nil {
+			return Format{}, err
+		}
+
+		if bytes.HasPrefix(head, []byte("#separator:")) || bytes.HasPrefix(head, []byte("#html:")) {
+			return Lookup("anki")
+		}
+	}
+
+	for _, f := range formats {
+		if slices.Contains(f.Extensions, ext) {
+			return f, nil
+		}
+	}
+
+	return Lookup("txt")
+}
+
+// readHead reads the beginning of a file without a byte order mark
+func readHead(filePath string) ([]byte, error) {
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+
+	return bytes.TrimPrefix(head[:n], utf8BOM), nil
+}
+
+// ReadFile reads the file at filePath in format
+func ReadFile(filePath string, format Format, options Options) (*dataFrame.DataFrame, error) {
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return format.Read(file, options)
+}
+
+// newTable creates a DataFrame holding imported fields and rows
+func newTable(fields []string, rows [][]string) *dataFrame.DataFrame {
+
+	df := dataFrame.NewDataFrame(';')
+	df.Columns = fields
+	df.Data = rows
+
+	return df
+}
+
+// numberedFields returns the names "Field 1" to "Field n"
+func numberedFields(n int) []string {
+
+	fields := make([]string, n)
+	for i := range fields {
+		fields[i] = fmt.Sprintf("Field %d", i+1)
+	}
+
+	return fields
+}
@@ -0,0 +1,230 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Your-RoGr/DeckBuilder/src/exporter"
+)
+
+func read(t *testing.T, name, data string, options Options) ([]string, [][]string) {
+
+	format, err := Lookup(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	df, err := format.Read(strings.NewReader(data), options)
+	if err != nil {
+		t.Fatalf("Read error: %v", err)
+	}
+
+	return df.Columns, df.Data
+}
+
+func TestReadJSON_objects(t *testing.T) {
+
+	fields, rows := read(t, "json", `[
+		{"word": "cat", "translation": "кошка", "tags": ["animals", "pets"]},
+		{"word": "one", "count": 1, "translation": null, "known": true}
+	]`, Options{})
+
+	if !reflect.DeepEqual(fields, []string{"word", "translation", "tags", "count", "known"}) {
+		t.Errorf("Fields mismatch: %v", fields)
+	}
+
+	expected := [][]string{
+		{"cat", "кошка", "animals pets", "", ""},
+		{"one", "", "", "1", "true"},
+	}
+
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Rows mismatch: %v", rows)
+	}
+}
+
+func TestReadJSON_arrays(t *testing.T) {
+
+	fields, rows := read(t, "json", `[["cat", "кошка"], ["dog"]]`, Options{})
+
+	if !reflect.DeepEqual(fields, []string{"Field 1", "Field 2"}) {
+		t.Errorf("Fields mismatch: %v", fields)
+	}
+
+	if !reflect.DeepEqual(rows, [][]string{{"cat", "кошка"}, {"dog"}}) {
+		t.Errorf("Rows mismatch: %v", rows)
+	}
+}
+
+func TestReadJSON_notArray(t *testing.T) {
+
+	format, _ := Lookup("json")
+
+	if _, err := format.Read(strings.NewReader(`{"word": "cat"}`), Options{}); err == nil {
+		t.Error("Expected an error for a JSON object")
+	}
+}
+
+func TestReadTXT(t *testing.T) {
+
+	_, rows := read(t, "txt", "\xEF\xBB\xBFcat - кошка\r\n\ndog-house - будка\nowl\n", Options{})
+
+	expected := [][]string{{"cat", "кошка"}, {"dog-house", "будка"}, {"owl"}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Rows mismatch: %v", rows)
+	}
+
+	_, rows = read(t, "txt", "cat=кошка\n", Options{Separator: "="})
+	if !reflect.DeepEqual(rows, [][]string{{"cat", "кошка"}}) {
+		t.Errorf("Rows with '=' mismatch: %v", rows)
+	}
+}
+
+func TestReadTSV_exported(t *testing.T) {
+
+	tsv, _ := exporter.Lookup("tsv")
+	df := newTable([]string{"Word", "Translation"}, [][]string{{"cat", "line\nbreak\tand \\"}})
+
+	var b strings.Builder
+	if err := exporter.Export(&b, df, tsv, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	fields, rows := read(t, "tsv", b.String(), Options{})
+
+	if !reflect.DeepEqual(fields, df.Columns) || !reflect.DeepEqual(rows, df.Data) {
+		t.Errorf("TSV did not round-trip: %v %v", fields, rows)
+	}
+}
+
+func TestReadAnki(t *testing.T) {
+
+	data := "#separator:tab\n#html:true\n#guid column:1\n#notetype column:2\n#deck column:3\n#tags column:6\n" +
+		"abc\tBasic\tEnglish\tcat\t\"<b>кошка</b><br>кот &amp; кошка\"\tanimals\n" +
+		"def\tBasic\tEnglish\tdog\tсобака\t\n"
+
+	fields, rows := read(t, "anki", data, Options{})
+
+	if !reflect.DeepEqual(fields, []string{"GUID", "Notetype", "Deck", "Field 1", "Field 2", "Tags"}) {
+		t.Errorf("Fields mismatch: %v", fields)
+	}
+
+	expected := [][]string{
+		{"abc", "Basic", "English", "cat", "кошка\nкот & кошка", "animals"},
+		{"def", "Basic", "English", "dog", "собака", ""},
+	}
+
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Rows mismatch: %q", rows)
+	}
+}
+
+func TestReadAPKG_exported(t *testing.T) {
+
+	apkg, _ := exporter.Lookup("apkg")
+	df := newTable([]string{"Word", "Translation", "Tags"}, [][]string{{"cat", "<b>кот</b>\nкошка", "animals"}})
+
+	var b strings.Builder
+	if err := exporter.Export(&b, df, apkg, "English"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		options  Options
+		expected []string
+	}{
+		{Options{}, []string{"cat", "<b>кот</b>\nкошка", "animals", "English"}},
+		{Options{KeepHTML: true}, []string{"cat", "&lt;b&gt;кот&lt;/b&gt;<br>кошка", "animals", "English"}},
+	}
+
+	for _, tt := range tests {
+
+		fields, rows := read(t, "apkg", b.String(), tt.options)
+
+		if !reflect.DeepEqual(fields, []string{"Word", "Translation", "Tags", "Deck"}) {
+			t.Errorf("Fields mismatch: %v", fields)
+		}
+
+		if !reflect.DeepEqual(rows, [][]string{tt.expected}) {
+			t.Errorf("Rows mismatch with %+v: %q", tt.options, rows)
+		}
+	}
+}
+
+func TestMapColumns(t *testing.T) {
+
+	columns := []string{"Word", "Translation", "Tags", "Deck"}
+
+	tests := []struct {
+		fields   []string
+		expected Mapping
+	}{
+		{[]string{"Field 1", "Field 2"}, Mapping{0, 1, -1, -1}},
+		{[]string{"Back", "Front", "tags"}, Mapping{1, 0, 2, -1}},
+		{[]string{"GUID", "Notetype", "Deck", "Field 1", "Field 2", "Tags"}, Mapping{3, 4, 5, 2}},
+		{[]string{"translation", "note"}, Mapping{1, 0, -1, -1}},
+	}
+
+	for _, tt := range tests {
+		if mapping := MapColumns(columns, tt.fields); !reflect.DeepEqual(mapping, tt.expected) {
+			t.Errorf("MapColumns(%v) = %v, want %v", tt.fields, mapping, tt.expected)
+		}
+	}
+
+	mapping := Mapping{1, -1}
+	if row := mapping.Row([]string{"cat", " кошка "}); !reflect.DeepEqual(row, []string{"кошка", ""}) {
+		t.Errorf("Row mismatch: %v", row)
+	}
+
+	fields := []string{"Front", "Back", "Example", "Tags", "Audio", "Deck"}
+	unmapped := MapColumns(columns, fields).Unmapped(fields)
+	if !reflect.DeepEqual(unmapped, []string{"Example", "Audio"}) {
+		t.Errorf("Unmapped mismatch: %v", unmapped)
+	}
+
+	lines := mapping.Lines([]string{"Word", "Tags"}, []string{"a", "b"})
+	if !reflect.DeepEqual(lines, []string{"Word ← b", "Tags ← (empty)"}) {
+		t.Errorf("Lines mismatch: %v", lines)
+	}
+}
+
+func TestDetect(t *testing.T) {
+
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"deck.json":   "[]",
+		"deck.tsv":    "Word\n",
+		"notes.txt":   "#separator:tab\ncat\tкошка\n",
+		"words.txt":   "cat - кошка\n",
+		"words.list":  "cat - кошка\n",
+		"shared.apkg": "",
+		"all.colpkg":  "",
+	}
+
+	expected := map[string]string{
+		"deck.json":   "json",
+		"deck.tsv":    "tsv",
+		"notes.txt":   "anki",
+		"words.txt":   "txt",
+		"words.list":  "txt",
+		"shared.apkg": "apkg",
+		"all.colpkg":  "apkg",
+	}
+
+	for name, data := range files {
+
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		format, err := Detect(path)
+		if err != nil || format.Name != expected[name] {
+			t.Errorf("Detect(%s) = %v, %v, want %s", name, format.Name, err, expected[name])
+		}
+	}
+}
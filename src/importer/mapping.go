@@ -0,0 +1,119 @@
+package importer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// synonyms lists the field names, in lower case, that are imported into a deck column
+var synonyms = map[string][]string{
+	"word":        {"word", "front", "term", "question", "expression"},
+	"translation": {"translation", "back", "meaning", "answer", "definition"},
+}
+
+// namedOnly lists, in lower case, the columns and fields that are matched by name only
+var namedOnly = []string{"tags", "deck", "notetype", "guid"}
+
+// Mapping maps the columns of a deck to the fields of an import:
+// Mapping[i] is the field imported into column i, or -1 if the column stays empty
+type Mapping []int
+
+// MapColumns proposes a Mapping of fields to columns. Fields are matched to columns by
+// name first; the remaining columns get the remaining fields in order, except tags,
+// deck, note type and GUID, which are only matched by name
+func MapColumns(columns, fields []string) Mapping {
+
+	mapping := make(Mapping, len(columns))
+	used := make([]bool, len(fields))
+
+	for i, column := range columns {
+
+		mapping[i] = -1
+
+		for j, field := range fields {
+			if !used[j] && sameName(column, field) {
+				mapping[i] = j
+				used[j] = true
+				break
+			}
+		}
+	}
+
+	next := 0
+
+	for i, column := range columns {
+
+		if mapping[i] != -1 || slices.Contains(namedOnly, strings.ToLower(column)) {
+			continue
+		}
+
+		for next < len(fields) && (used[next] || slices.Contains(namedOnly, strings.ToLower(fields[next]))) {
+			next++
+		}
+
+		if next == len(fields) {
+			break
+		}
+
+		mapping[i] = next
+		used[next] = true
+	}
+
+	return mapping
+}
+
+// sameName reports whether field holds the values of column
+func sameName(column, field string) bool {
+
+	column, field = strings.ToLower(strings.TrimSpace(column)), strings.ToLower(strings.TrimSpace(field))
+
+	return column == field || slices.Contains(synonyms[column], field)
+}
+
+// Row returns the values of the columns taken from the fields of row
+func (m Mapping) Row(row []string) []string {
+
+	values := make([]string, len(m))
+
+	for i, field := range m {
+		if field != -1 && field < len(row) {
+			values[i] = strings.TrimSpace(row[field])
+		}
+	}
+
+	return values
+}
+
+// Unmapped returns the fields that are imported into no column, except tags, deck,
+// note type and GUID, in the order of fields
+func (m Mapping) Unmapped(fields []string) []string {
+
+	var unmapped []string
+
+	for j, field := range fields {
+		if !slices.Contains(m, j) && !slices.Contains(namedOnly, strings.ToLower(field)) {
+			unmapped = append(unmapped, field)
+		}
+	}
+
+	return unmapped
+}
+
+// Lines describes the mapping as "column ← field" lines for a preview
+func (m Mapping) Lines(columns, fields []string) []string {
+
+	lines := make([]string, len(m))
+
+	for i, field := range m {
+
+		name := "(empty)"
+		if field != -1 {
+			name = fields[field]
+		}
+
+		lines[i] = fmt.Sprintf("%s ← %s", columns[i], name)
+	}
+
+	return lines
+}
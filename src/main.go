@@ -2,12 +2,21 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/Your-RoGr/DeckBuilder/src/app"
 )
 
 func main() {
 
+	// Commands such as "export" run without the menu
+	if ok, err := app.RunCommand(os.Args[1:], os.Stdout); ok {
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	err := app.NewMainMenu().Start()
 
 	if err != nil {